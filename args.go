@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// ArgValues are the inputs of an operation passed to E for debugging.
+// They are rendered only by %+v and never by client-facing renderers.
+type ArgValues []interface{}
+
+// Args returns ArgValues of vals. The values are stored as they are
+// and rendered with %+v only when formatted.
+func Args(vals ...interface{}) ArgValues {
+	return ArgValues(vals)
+}
+
+// ArgsOf returns the args of the layers which have them
+// from outermost to innermost.
+func ArgsOf(err error) [][]interface{} {
+	var args [][]interface{}
+	walk(err, func(e *appError) bool {
+		if len(e.args) > 0 {
+			args = append(args, e.args)
+		}
+		return true
+	})
+	return args
+}
+
+var argLimits atomic.Value
+
+type argLimit struct {
+	value, total int
+}
+
+func init() {
+	argLimits.Store(argLimit{value: 256, total: 1024})
+}
+
+// SetArgsLimits sets the maximum lengths of a rendered arg
+// and of all the rendered args of a layer. They default to 256 and 1024.
+func SetArgsLimits(perValue, total int) {
+	argLimits.Store(argLimit{value: perValue, total: total})
+}
+
+// String renders the values with %+v, capped and redacted.
+func (a ArgValues) String() string {
+	limit := argLimits.Load().(argLimit)
+	var b strings.Builder
+	for i, v := range a {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(truncate(fmt.Sprintf("%+v", v), limit.value))
+		if b.Len() >= limit.total {
+			break
+		}
+	}
+	return redact(truncate(b.String(), limit.total))
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 0 || len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,65 @@
+package errors_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+type query struct {
+	Table string
+	Limit int
+}
+
+func TestArgsOf(t *testing.T) {
+	err := errors.E("app.Handle", errors.Args("u1"),
+		errors.E("app.Load", "no args",
+			fmt.Errorf("list: %w", errors.E("db.List", errors.Args(query{"users", 10}, 3)))))
+
+	want := [][]interface{}{{"u1"}, {query{"users", 10}, 3}}
+	if got := errors.ArgsOf(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("ArgsOf() = %v, want %v", got, want)
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "args: {Table:users Limit:10}, 3") {
+		t.Errorf("%%+v = %q, want the args with %%+v", got)
+	}
+	for _, s := range []string{errors.Msg(err), errors.ClientMsg(err), err.Error()} {
+		if strings.Contains(s, "users") {
+			t.Errorf("%q has the args", s)
+		}
+	}
+}
+
+func TestSetArgsLimits(t *testing.T) {
+	defer errors.SetArgsLimits(256, 1024)
+
+	tests := map[string]struct {
+		perValue, total int
+		args            errors.ArgValues
+		want            string
+	}{
+		"defaults": {256, 1024, errors.Args("abc", 12), "abc, 12"},
+		"per value": {
+			4, 1024,
+			errors.Args("abcdef", "ab", "日本語テキスト"),
+			"abcd…, ab, 日本語テ…",
+		},
+		"total": {
+			256, 10,
+			errors.Args("abcdef", "ghijkl", "mnopqr"),
+			"abcdef, gh…",
+		},
+		"unlimited": {0, 0, errors.Args(strings.Repeat("a", 300)), strings.Repeat("a", 300)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			errors.SetArgsLimits(tt.perValue, tt.total)
+			if got := tt.args.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"iter"
+	"strconv"
+	"sync"
+)
+
+// Batch collects per-item failures of bulk operations.
+// The zero value is ready to use. It is safe for concurrent use.
+type Batch struct {
+	// Op is the op of the error returned by Err.
+	Op Op
+	// Total is the number of items, used in the summary message.
+	Total int
+
+	mu    sync.Mutex
+	items []batchItem
+}
+
+type batchItem struct {
+	index int
+	err   error
+}
+
+// NewBatch returns a Batch for total items.
+func NewBatch(op Op, total int) *Batch {
+	return &Batch{Op: op, Total: total}
+}
+
+// Add records the failure of the item at index. Nil errors are ignored.
+func (b *Batch) Add(index int, err error) {
+	if err == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items = append(b.items, batchItem{index: index, err: err})
+}
+
+// Len returns the number of failures.
+func (b *Batch) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.items)
+}
+
+// All returns an iterator over the indexes and the errors of failures.
+func (b *Batch) All() iter.Seq2[int, error] {
+	b.mu.Lock()
+	items := append([]batchItem(nil), b.items...)
+	b.mu.Unlock()
+
+	return func(yield func(int, error) bool) {
+		for _, it := range items {
+			if !yield(it.index, it.err) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns an error which wraps all the failures, or nil if empty.
+// Its kind is the most severe kind among the failures and its message
+// summarizes them like "3 of 500 items failed".
+func (b *Batch) Err() error {
+	b.mu.Lock()
+	items := append([]batchItem(nil), b.items...)
+	b.mu.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	var kind KindCode
+	errs := make([]error, len(items))
+	for i, it := range items {
+		errs[i] = it.err
+		if k := Kind(it.err); k > kind {
+			kind = k
+		}
+	}
+
+	msg := strconv.Itoa(len(items)) + " items failed"
+	if b.Total > 0 {
+		msg = strconv.Itoa(len(items)) + " of " + strconv.Itoa(b.Total) + " items failed"
+	}
+
+	e := newAppError(b.Op)
+	e.batch = items
+	e.kind, e.msg = kind, msg
+	e.finish(1, []error{&joinError{errs: errs}}, nil)
+	notify(e)
+	return e
+}
+
+type batchEntry struct {
+	Index int      `json:"index"`
+	Kind  KindCode `json:"kind"`
+	Msg   string   `json:"msg"`
+}
+
+// batchEntries returns the per-index breakdown of the outermost batch
+// with messages safe for clients.
+func batchEntries(err error) []batchEntry {
+	var entries []batchEntry
+	walk(err, func(e *appError) bool {
+		if e.batch == nil {
+			return true
+		}
+		for _, it := range e.batch {
+			entries = append(entries, batchEntry{Index: it.index, Kind: Kind(it.err), Msg: publicMsg(it.err)})
+		}
+		return false
+	})
+	return entries
+}
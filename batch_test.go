@@ -0,0 +1,40 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestBatchMsg(t *testing.T) {
+	b := errors.NewBatch("app.Import", 500)
+	b.Add(3, errors.E("app.Parse", errors.KindBadRequest, "bad row"))
+	b.Add(7, errors.E("app.Parse", errors.KindBadRequest, "bad date"))
+	err := errors.E("app.Handle", "import", b.Err())
+
+	if got, want := errors.Msg(err), "import: 2 of 500 items failed"; got != want {
+		t.Errorf("Msg() = %q, want %q", got, want)
+	}
+
+	body, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	for _, msg := range []string{`"index":3`, `"bad row"`, `"index":7`, `"bad date"`} {
+		if !strings.Contains(string(body), msg) {
+			t.Errorf("JSON %s does not contain %s", body, msg)
+		}
+	}
+}
+
+func TestBatchMsgSiblings(t *testing.T) {
+	b := errors.NewBatch("app.Import", 0)
+	b.Add(0, errors.E("app.Parse", "bad row"))
+	err := errors.E("app.Handle", b.Err(), errors.E("app.Audit", "audit failed"))
+
+	if got, want := errors.Msg(err), "1 items failed: audit failed"; got != want {
+		t.Errorf("Msg() = %q, want %q", got, want)
+	}
+}
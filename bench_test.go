@@ -0,0 +1,139 @@
+package errors_test
+
+import (
+	"context"
+	stderrors "errors"
+	"runtime/pprof"
+	"strconv"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+var (
+	errRoot = stderrors.New("root")
+	sink    error
+)
+
+func BenchmarkEStackCapture(b *testing.B) {
+	modes := []struct {
+		name string
+		mode errors.StackMode
+	}{
+		{"Always", errors.StackAlways},
+		{"Never", errors.StackNever},
+		{"ErrorLevelOnly", errors.StackErrorLevelOnly},
+	}
+	for _, m := range modes {
+		b.Run(m.name, func(b *testing.B) {
+			errors.SetStackCapture(m.mode)
+			defer errors.SetStackCapture(errors.StackAlways)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sink = errors.E("app.Get", errRoot, errors.KindNotFound, log.LevelInfo)
+			}
+		})
+	}
+}
+
+func BenchmarkDeepChain(b *testing.B) {
+	for _, depth := range []int{10, 128, 40000} {
+		err := chain(depth)
+		b.Run(strconv.Itoa(depth), func(b *testing.B) {
+			b.Run("Kind", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = errors.Kind(err)
+				}
+			})
+			b.Run("Msg", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = errors.Msg(err)
+				}
+			})
+			b.Run("Ops", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = errors.Ops(err)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkECaptureGoroutineInfo(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		b.Run(strconv.FormatBool(enabled), func(b *testing.B) {
+			errors.CaptureGoroutineInfo(enabled)
+			defer errors.CaptureGoroutineInfo(false)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sink = errors.E("app.Get", errRoot, errors.KindNotFound)
+			}
+		})
+	}
+}
+
+func BenchmarkECCaptureGoroutineInfo(b *testing.B) {
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("handler", "get"))
+	for _, enabled := range []bool{false, true} {
+		b.Run(strconv.FormatBool(enabled), func(b *testing.B) {
+			errors.CaptureGoroutineInfo(enabled)
+			defer errors.CaptureGoroutineInfo(false)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sink = errors.EC(ctx, "app.Get", errRoot, errors.KindNotFound)
+			}
+		})
+	}
+}
+
+func BenchmarkE(b *testing.B) {
+	b.Run("E", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sink = errors.E("app.Get", errRoot, "get user", errors.KindNotFound)
+		}
+	})
+	b.Run("E2", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sink = errors.E2("app.Get", errRoot, "get user", errors.KindNotFound)
+		}
+	})
+}
+
+func BenchmarkWrapDeep(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := errRoot
+		for j := 0; j < 32; j++ {
+			err = errors.E("app.Retry", err, "retry")
+		}
+		sink = err
+	}
+}
+
+func BenchmarkMsgDeep(b *testing.B) {
+	err := errRoot
+	for j := 0; j < 100; j++ {
+		err = errors.E("app.Retry", err, "retry")
+	}
+	orders := []struct {
+		name  string
+		order errors.MsgOrder
+	}{
+		{"OuterFirst", errors.OuterFirst},
+		{"InnerFirst", errors.InnerFirst},
+	}
+	for _, o := range orders {
+		b.Run(o.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = errors.JoinMsg(err, ": ", o.order)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+type buildInfo struct {
+	version, host string
+}
+
+var (
+	buildInfos       atomic.Value
+	defaultBuildInfo = sync.OnceValue(func() buildInfo {
+		var b buildInfo
+		if info, ok := debug.ReadBuildInfo(); ok {
+			b.version = info.Main.Version
+		}
+		b.host, _ = os.Hostname()
+		return b
+	})
+)
+
+// SetBuildInfo sets the service, the version and the host
+// which produce errors. They are not stored in errors but added when
+// errors are rendered by MarshalJSON, Encode, SlogValue, Summary
+// and LogPayload.
+// Empty version and host default to the version of the main module
+// and the hostname, which are used without calling SetBuildInfo.
+// The service is the same as the one set by SetServiceName.
+// Fields "service", "version" and "host" of an error override them.
+func SetBuildInfo(service, version, host string) {
+	d := defaultBuildInfo()
+	if version == "" {
+		version = d.version
+	}
+	if host == "" {
+		host = d.host
+	}
+	SetServiceName(service)
+	buildInfos.Store(buildInfo{version: version, host: host})
+}
+
+// buildOf returns the service, the version and the host of err.
+func buildOf(err error) (string, string, string) {
+	b, ok := buildInfos.Load().(buildInfo)
+	if !ok {
+		b = defaultBuildInfo()
+	}
+	svc, version, host := service(), b.version, b.host
+	fields := FieldsOf(err)
+	override := func(dst *string, key string) {
+		if s, ok := fields[key].(string); ok && s != "" {
+			*dst = s
+		}
+	}
+	override(&svc, "service")
+	override(&version, "version")
+	override(&host, "host")
+	return svc, version, host
+}
@@ -0,0 +1,50 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestSetBuildInfo(t *testing.T) {
+	errors.SetBuildInfo("users", "v1.2.3", "web-1")
+	defer errors.SetBuildInfo("", "", "")
+
+	tests := map[string]struct {
+		err                    error
+		service, version, host string
+	}{
+		"build info": {errors.E("app.Get", errors.KindNotFound), "users", "v1.2.3", "web-1"},
+		"fields": {
+			errors.E("app.Get", errors.KindNotFound, errors.Fields{"version": "v1.2.4-rc1", "host": "web-2"}),
+			"users", "v1.2.4-rc1", "web-2",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := errors.MarshalJSON(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc struct{ Service, Version, Host string }
+			if err := json.Unmarshal(b, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if doc.Service != tt.service || doc.Version != tt.version || doc.Host != tt.host {
+				t.Errorf("MarshalJSON() = %s, want %s, %s and %s", b, tt.service, tt.version, tt.host)
+			}
+
+			s := errors.Summary(tt.err)
+			if s.Service != tt.service || s.Version != tt.version || s.Host != tt.host {
+				t.Errorf("Summary() = %+v, want %s, %s and %s", s, tt.service, tt.version, tt.host)
+			}
+
+			want := map[string]interface{}{"service": tt.service, "version": tt.version}
+			if got := errors.LogPayload(tt.err)["serviceContext"]; !reflect.DeepEqual(got, want) {
+				t.Errorf("LogPayload() serviceContext = %v, want %v", got, want)
+			}
+		})
+	}
+}
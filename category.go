@@ -0,0 +1,48 @@
+package errors
+
+import "sync"
+
+// Uncategorized is the category of errors whose kinds
+// belong to no category.
+const Uncategorized = "uncategorized"
+
+// InCategory can be passed to E to set the category explicitly,
+// overriding the category of the kind.
+type InCategory string
+
+var categories = struct {
+	sync.RWMutex
+	m map[KindCode]string
+}{m: map[KindCode]string{}}
+
+// DefineCategory assigns the kinds to the category named name.
+// A kind belongs to at most one category; later definitions win.
+func DefineCategory(name string, kinds ...KindCode) {
+	categories.Lock()
+	defer categories.Unlock()
+	for _, k := range kinds {
+		categories.m[k] = name
+	}
+}
+
+// Category returns the category of the error.
+// The outermost category set with InCategory takes precedence
+// over the category of the error's kind.
+// It returns Uncategorized if neither is found.
+func Category(err error) string {
+	var category InCategory
+	walk(err, func(e *appError) bool {
+		category = e.category
+		return category == ""
+	})
+	if category != "" {
+		return string(category)
+	}
+
+	categories.RLock()
+	defer categories.RUnlock()
+	if name, ok := categories.m[Kind(err)]; ok {
+		return name
+	}
+	return Uncategorized
+}
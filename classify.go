@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"os"
+	"sync"
+)
+
+var builtinClassifications = []struct {
+	target error
+	kind   KindCode
+}{
+	{sql.ErrNoRows, KindNotFound},
+	{context.DeadlineExceeded, KindGatewayTimeout},
+	{context.Canceled, KindClientClosedRequest},
+	{os.ErrNotExist, KindNotFound},
+	{os.ErrPermission, KindForbidden},
+}
+
+var classifiers struct {
+	sync.RWMutex
+	fns []func(error) (KindCode, bool)
+}
+
+// RegisterClassifier registers fn to be consulted by Classify.
+// Registered classifiers are consulted in order
+// before the built-in classifications.
+func RegisterClassifier(fn func(error) (KindCode, bool)) {
+	classifiers.Lock()
+	defer classifiers.Unlock()
+	classifiers.fns = append(classifiers.fns, fn)
+}
+
+func classify(err error) (KindCode, bool) {
+	classifiers.RLock()
+	fns := classifiers.fns
+	classifiers.RUnlock()
+
+	for _, fn := range fns {
+		if kind, ok := fn(err); ok {
+			return kind, true
+		}
+	}
+	for _, c := range builtinClassifications {
+		if stderrors.Is(err, c.target) {
+			return c.kind, true
+		}
+	}
+	return 0, false
+}
+
+// Classify wraps err with op and assigns a kind
+// determined from well-known errors in its chain.
+// If nothing matches, the kind is left to the chain
+// and defaults to KindUnexpected. It returns nil if err is nil.
+func Classify(op Op, err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind, ok := classify(err); ok {
+		return newError(1, op, []interface{}{err, kind})
+	}
+	return newError(1, op, []interface{}{err})
+}
+
+// Reclassify wraps err with op and the kind mapped by mapping
+// from the effective kind of err, e.g. to report KindNotFound of
+// a dependency as KindBadRequest at an API boundary. Unmapped kinds
+// are left to the chain as they are. The kind before reclassification is returned by OriginalKind.
+// It returns nil if err is nil.
+func Reclassify(op Op, err error, mapping map[KindCode]KindCode) error {
+	if isNil(err) {
+		return nil
+	}
+	e := newAppError(op)
+	e.err = err
+	if from := Kind(err); mapping[from] != 0 && mapping[from] != from {
+		e.reclassifiedFrom, e.kind = from, mapping[from]
+	}
+	e.finish(1, nil, nil)
+	notify(e)
+	return e
+}
+
+// OriginalKind returns the kind of err before the innermost
+// reclassification by Reclassify. ok is false if err was never reclassified.
+func OriginalKind(err error) (kind KindCode, ok bool) {
+	walk(err, func(e *appError) bool {
+		if e.reclassifiedFrom != 0 {
+			kind, ok = e.reclassifiedFrom, true
+		}
+		return true
+	})
+	return kind, ok
+}
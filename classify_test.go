@@ -0,0 +1,120 @@
+package errors_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want errors.KindCode
+	}{
+		{sql.ErrNoRows, errors.KindNotFound},
+		{fmt.Errorf("query: %w", sql.ErrNoRows), errors.KindNotFound},
+		{context.DeadlineExceeded, errors.KindGatewayTimeout},
+		{context.Canceled, errors.KindClientClosedRequest},
+		{os.ErrNotExist, errors.KindNotFound},
+		{os.ErrPermission, errors.KindForbidden},
+		{fmt.Errorf("other"), errors.KindUnexpected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if got := errors.Kind(errors.Classify("app.Get", tt.err)); got != tt.want {
+				t.Errorf("Kind() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	if err := errors.Classify("app.Get", nil); err != nil {
+		t.Errorf("Classify(nil) = %v, want nil", err)
+	}
+}
+
+func TestClassifyStack(t *testing.T) {
+	pcs := errors.StackPCs(errors.Classify("app.Get", sql.ErrNoRows))
+	if len(pcs) == 0 {
+		t.Fatal("no program counters captured")
+	}
+	fr, _ := runtime.CallersFrames(pcs).Next()
+	if !strings.HasSuffix(fr.Function, ".TestClassifyStack") {
+		t.Errorf("first frame = %s, want the caller of Classify", fr.Function)
+	}
+}
+
+func TestRegisterClassifierReentrant(t *testing.T) {
+	once := false
+	errors.RegisterClassifier(func(err error) (errors.KindCode, bool) {
+		if !once {
+			once = true
+			errors.RegisterClassifier(func(error) (errors.KindCode, bool) { return 0, false })
+		}
+		return 0, false
+	})
+	if got := errors.Kind(errors.Classify("app.Get", sql.ErrNoRows)); got != errors.KindNotFound {
+		t.Errorf("Kind() = %d, want %d", got, errors.KindNotFound)
+	}
+}
+
+func TestReclassify(t *testing.T) {
+	mapping := map[errors.KindCode]errors.KindCode{errors.KindNotFound: errors.KindBadRequest}
+	tests := map[string]struct {
+		err          error
+		wantKind     errors.KindCode
+		wantExplicit bool
+		wantOriginal errors.KindCode
+		wantLevel    log.Level
+	}{
+		"mapped":   {errors.E("app.Get", errors.KindNotFound), errors.KindBadRequest, true, errors.KindNotFound, log.LevelError},
+		"unmapped": {errors.E("app.Get", errors.KindConflict), errors.KindConflict, true, 0, log.LevelError},
+		"default":  {errors.E("app.Get", "failed"), errors.KindUnexpected, false, 0, log.LevelError},
+		"canceled": {errors.E("app.Get", context.Canceled), errors.KindClientClosedRequest, false, 0, log.LevelWarn},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := errors.Reclassify("api.Get", tt.err, mapping)
+
+			if got := errors.Kind(err); got != tt.wantKind {
+				t.Errorf("Kind() = %d, want %d", got, tt.wantKind)
+			}
+			if _, ok := errors.KindExplicit(err); ok != tt.wantExplicit {
+				t.Errorf("KindExplicit() ok = %v, want %v", ok, tt.wantExplicit)
+			}
+			if got := errors.Level(err); got != tt.wantLevel {
+				t.Errorf("Level() = %v, want %v", got, tt.wantLevel)
+			}
+			got, ok := errors.OriginalKind(err)
+			if got != tt.wantOriginal || ok != (tt.wantOriginal != 0) {
+				t.Errorf("OriginalKind() = %d, %v, want %d", got, ok, tt.wantOriginal)
+			}
+			reclassified := strings.Contains(fmt.Sprintf("%+v", err), "kind: 400 Bad Request (reclassified from 404)")
+			if reclassified != (tt.wantOriginal != 0) {
+				t.Errorf("%%+v reclassified = %v, want %v", reclassified, tt.wantOriginal != 0)
+			}
+		})
+	}
+	if err := errors.Reclassify("api.Get", nil, mapping); err != nil {
+		t.Errorf("Reclassify(nil) = %v, want nil", err)
+	}
+}
+
+func TestOriginalKindInnermost(t *testing.T) {
+	err := errors.Reclassify("api.Get", errors.E("app.Get", errors.KindNotFound),
+		map[errors.KindCode]errors.KindCode{errors.KindNotFound: errors.KindBadRequest})
+	err = errors.Reclassify("gateway.Get", err,
+		map[errors.KindCode]errors.KindCode{errors.KindBadRequest: errors.KindUnprocessableEntity})
+
+	if got := errors.Kind(err); got != errors.KindUnprocessableEntity {
+		t.Errorf("Kind() = %d, want %d", got, errors.KindUnprocessableEntity)
+	}
+	if got, ok := errors.OriginalKind(err); !ok || got != errors.KindNotFound {
+		t.Errorf("OriginalKind() = %d, %v, want %d", got, ok, errors.KindNotFound)
+	}
+}
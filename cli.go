@@ -0,0 +1,93 @@
+package errors
+
+import (
+	stderrors "errors"
+	"strings"
+	"sync"
+)
+
+var exitCodes = struct {
+	sync.RWMutex
+	m map[KindCode]int
+}{m: map[KindCode]int{
+	KindBadRequest:         64, // EX_USAGE
+	KindForbidden:          77, // EX_NOPERM
+	KindServiceUnavailable: 69, // EX_UNAVAILABLE
+}}
+
+// RegisterExitCode registers the exit code returned by ExitCode for kind.
+func RegisterExitCode(kind KindCode, code int) {
+	exitCodes.Lock()
+	defer exitCodes.Unlock()
+	exitCodes.m[kind] = code
+}
+
+// ExitCode returns the conventional exit code for the error's kind.
+// It returns 0 for nil and 1 for kinds without exit codes.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	exitCodes.RLock()
+	defer exitCodes.RUnlock()
+	if code, ok := exitCodes.m[Kind(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// Render renders the error for end users of CLIs: the headline message
+// followed by an indented "caused by" chain. In verbose mode,
+// the op and the location of each layer are also rendered.
+func Render(err error, verbose bool) string {
+	if err == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	n := 0
+	entry := func(msg string, e *appError) {
+		if n == 0 {
+			b.WriteString(msg)
+		} else {
+			b.WriteString("\n  caused by: ")
+			b.WriteString(msg)
+		}
+		n++
+		if !verbose || e == nil {
+			return
+		}
+		if e.op != "" {
+			b.WriteString("\n      op: ")
+			b.WriteString(string(e.op))
+		}
+		if fr, ok := e.location(); ok {
+			b.WriteString("\n      at: ")
+			b.WriteString(fr.String())
+		}
+	}
+
+	visit(err, func(c error) bool {
+		if e, ok := c.(*appError); ok {
+			switch {
+			case e.msg != "":
+				entry(e.msg, e)
+			case verbose:
+				entry("(no message)", e)
+			}
+			return true
+		}
+		if _, ok := c.(interface{ Unwrap() []error }); ok {
+			return true
+		}
+		if stderrors.Unwrap(c) == nil {
+			entry(c.Error(), nil)
+		}
+		return true
+	})
+
+	if n == 0 {
+		return KindText(err)
+	}
+	return b.String()
+}
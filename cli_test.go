@@ -0,0 +1,74 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestExitCode(t *testing.T) {
+	errors.RegisterExitCode(errors.KindNotFound, 66) // EX_NOINPUT
+	defer errors.RegisterExitCode(errors.KindNotFound, 1)
+
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"nil":         {nil, 0},
+		"plain":       {stderrors.New("boom"), 1},
+		"bad request": {errors.E("cli.Parse", errors.KindBadRequest), 64},
+		"forbidden":   {errors.E("cli.Open", errors.KindForbidden), 77},
+		"unavailable": {errors.E("cli.Dial", errors.KindServiceUnavailable), 69},
+		"unexpected":  {errors.E("cli.Run", "boom"), 1},
+		"registered":  {errors.E("cli.Open", errors.KindNotFound), 66},
+		"wrapped":     {fmt.Errorf("run: %w", errors.E("cli.Parse", errors.KindBadRequest)), 64},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	err := errors.E("cli.Run", "cannot run",
+		errors.E("cli.Load", errors.KindNotFound,
+			fmt.Errorf("open: %w", stderrors.New("no such file"))))
+	tests := map[string]struct {
+		err     error
+		verbose bool
+		want    string
+	}{
+		"nil": {nil, false, ""},
+		"chain": {err, false, "cannot run\n" +
+			"  caused by: no such file"},
+		"verbose": {err, true, "cannot run\n" +
+			"      op: cli.Run\n" +
+			"      at: .*/cli_test.go:\\d+ \\(go.nownabe.dev/errors_test.TestRender\\)\n" +
+			"  caused by: \\(no message\\)\n" +
+			"      op: cli.Load\n" +
+			"      at: .*/cli_test.go:\\d+ \\(go.nownabe.dev/errors_test.TestRender\\)\n" +
+			"  caused by: no such file"},
+		"no message": {errors.E("cli.Load", errors.KindNotFound), false, "Not Found"},
+		"plain":      {stderrors.New("boom"), false, "boom"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := errors.Render(tt.err, tt.verbose)
+			if !tt.verbose {
+				if got != tt.want {
+					t.Errorf("Render() = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			if !regexp.MustCompile("^" + tt.want + "$").MatchString(got) {
+				t.Errorf("Render() =\n%s\nwant matching\n%s", got, tt.want)
+			}
+		})
+	}
+}
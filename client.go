@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxResponseBody bounds the body read by FromHTTPResponse.
+const maxResponseBody = 4 << 10
+
+// FromHTTPResponse constructs an error from a response of a downstream
+// service. The kind is taken from the status code and the message from
+// the body, preferring "detail" of problem details and "message" of
+// WriteHTTP. The URL and the method are attached as fields.
+// It returns nil for 2xx responses. 3xx responses are KindUnexpected
+// unless the status code is registered with RegisterKind.
+// The caller is responsible for closing the body.
+func FromHTTPResponse(op Op, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	kind := KindCode(resp.StatusCode)
+	if kind < 400 && !registeredKind(kind) {
+		kind = KindUnexpected
+	}
+
+	args := []interface{}{kind}
+	if msg := responseMessage(resp); msg != "" {
+		args = append(args, msg)
+	}
+	if resp.Request != nil {
+		fields := Fields{"method": resp.Request.Method}
+		if resp.Request.URL != nil {
+			fields["url"] = resp.Request.URL.String()
+		}
+		args = append(args, fields)
+	}
+
+	return newError(1, op, args)
+}
+
+func responseMessage(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return ""
+	}
+
+	var doc struct {
+		Detail  string `json:"detail"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &doc) == nil {
+		if doc.Detail != "" {
+			return doc.Detail
+		}
+		if doc.Message != "" {
+			return doc.Message
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
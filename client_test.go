@@ -0,0 +1,81 @@
+package errors_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+// trackedBody records whether the body was closed.
+type trackedBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestFromHTTPResponse(t *testing.T) {
+	tests := map[string]struct {
+		status int
+		body   string
+		kind   errors.KindCode
+		msg    string
+	}{
+		"ok":           {http.StatusOK, "", 0, ""},
+		"no content":   {http.StatusNoContent, "", 0, ""},
+		"not found":    {http.StatusNotFound, "no user", errors.KindNotFound, "no user"},
+		"problem":      {http.StatusConflict, `{"title":"Conflict","detail":"version mismatch"}`, errors.KindConflict, "version mismatch"},
+		"message":      {http.StatusBadRequest, `{"message":"bad id"}`, errors.KindBadRequest, "bad id"},
+		"other json":   {http.StatusBadRequest, `{"code":1}`, errors.KindBadRequest, `{"code":1}`},
+		"server error": {http.StatusBadGateway, "upstream down\n", http.StatusBadGateway, "upstream down"},
+		"redirect":     {http.StatusFound, "", errors.KindUnexpected, "Internal Server Error"},
+		"empty body":   {http.StatusServiceUnavailable, "", errors.KindServiceUnavailable, "Service Unavailable"},
+		// 4KiB of the body is read, and Msg shows 1KiB of it.
+		"truncated body": {http.StatusBadRequest, strings.Repeat("a", 5<<10), errors.KindBadRequest, strings.Repeat("a", 1<<10) + "… (3072 bytes omitted)"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := &trackedBody{Reader: strings.NewReader(tt.body)}
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Body:       body,
+				Request:    httptest.NewRequest(http.MethodGet, "http://users.example.com/users/1", nil),
+			}
+			err := errors.FromHTTPResponse("client.GetUser", resp)
+			if body.closed {
+				t.Error("FromHTTPResponse() closed the body")
+			}
+			if tt.kind == 0 {
+				if err != nil {
+					t.Errorf("FromHTTPResponse() = %v, want nil", err)
+				}
+				return
+			}
+			if got := errors.Kind(err); got != tt.kind {
+				t.Errorf("Kind() = %d, want %d", got, tt.kind)
+			}
+			if got := errors.Msg(err); got != tt.msg {
+				t.Errorf("Msg() = %q, want %q", got, tt.msg)
+			}
+			fields := errors.FieldsOf(err)
+			if fields["method"] != http.MethodGet || fields["url"] != "http://users.example.com/users/1" {
+				t.Errorf("FieldsOf() = %v, want the method and the URL", fields)
+			}
+		})
+	}
+}
+
+func TestFromHTTPResponseRegistered(t *testing.T) {
+	errors.RegisterKind(http.StatusNotModified, "Not Modified")
+	resp := &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody}
+	if got := errors.Kind(errors.FromHTTPResponse("client.GetUser", resp)); got != http.StatusNotModified {
+		t.Errorf("Kind() = %d, want %d", got, http.StatusNotModified)
+	}
+}
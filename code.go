@@ -0,0 +1,55 @@
+package errors
+
+import "sync"
+
+// Code is a stable machine-readable code of an error such as
+// "project_not_found". It can be passed to E and is independent of kinds.
+type Code string
+
+type codeDefault struct {
+	kind KindCode
+	msg  string
+}
+
+var codes = struct {
+	sync.RWMutex
+	m map[Code]codeDefault
+}{m: map[Code]codeDefault{}}
+
+// RegisterCode registers the default kind and message of the code
+// used by ByCode.
+func RegisterCode(code Code, kind KindCode, msg string) {
+	codes.Lock()
+	defer codes.Unlock()
+	codes.m[code] = codeDefault{kind: kind, msg: msg}
+}
+
+// ByCode constructs an error with the code and its default kind
+// and message registered with RegisterCode.
+// args are passed to E and can override them.
+func ByCode(op Op, code Code, args ...interface{}) error {
+	codes.RLock()
+	d, ok := codes.m[code]
+	codes.RUnlock()
+
+	defaults := []interface{}{code}
+	if ok {
+		defaults = append(defaults, d.kind, d.msg)
+	}
+	return newError(1, op, append(defaults, args...))
+}
+
+// CodeOf returns the outermost code set in err's chain, or "".
+func CodeOf(err error) Code {
+	var code Code
+	walk(err, func(e *appError) bool {
+		code = e.code
+		return code == ""
+	})
+	return code
+}
+
+// IsCode reports whether err's code returned by CodeOf is code.
+func IsCode(err error, code Code) bool {
+	return code != "" && CodeOf(err) == code
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	stderrors "errors"
+	"slices"
+
+	"go.nownabe.dev/log"
+)
+
+type layer struct {
+	op    Op
+	kind  KindCode
+	level log.Level
+	msg   string
+	text  string
+}
+
+// layers returns the comparable contents of err's tree.
+// Errors not constructed by E are represented by their texts
+// only when they wrap nothing.
+func layers(err error) []layer {
+	var ls []layer
+	visit(err, func(err error) bool {
+		switch e := err.(type) {
+		case *appError:
+			ls = append(ls, layer{op: e.op, kind: e.kind, level: e.level, msg: e.msg})
+		case interface{ Unwrap() []error }:
+		default:
+			if stderrors.Unwrap(err) == nil {
+				ls = append(ls, layer{text: err.Error()})
+			}
+		}
+		return true
+	})
+	return ls
+}
+
+// EquivalentTo reports whether a and b have the same ops, kinds,
+// levels and messages in each layer. Stack frames, timestamps
+// and fields are ignored. Errors not constructed by E
+// are compared by their texts.
+func EquivalentTo(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return slices.Equal(layers(a), layers(b))
+}
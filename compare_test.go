@@ -0,0 +1,46 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestEquivalentTo(t *testing.T) {
+	get := func(args ...interface{}) error {
+		return errors.E("app.Get", append([]interface{}{errors.KindNotFound, "no user"}, args...)...)
+	}
+	tests := map[string]struct {
+		a, b error
+		want bool
+	}{
+		"nil":            {nil, nil, true},
+		"nil and error":  {nil, get(), false},
+		"same":           {get(), get(), true},
+		"stackless":      {get(), get(errors.NoStack), true},
+		"fields":         {get(), get(errors.Fields{"user": "u1"}), true},
+		"kind":           {get(), errors.E("app.Get", errors.KindGone, "no user"), false},
+		"op":             {get(), errors.E("app.Find", errors.KindNotFound, "no user"), false},
+		"msg":            {get(), errors.E("app.Get", errors.KindNotFound, "no item"), false},
+		"level":          {get(), get(log.LevelWarn), false},
+		"plain":          {stderrors.New("eof"), stderrors.New("eof"), true},
+		"plain text":     {stderrors.New("eof"), stderrors.New("closed"), false},
+		"plain wrappers": {get(fmt.Errorf("query: %w", stderrors.New("eof"))), get(fmt.Errorf("exec: %w", stderrors.New("eof"))), true},
+		"chain": {
+			errors.E("app.Handle", get(stderrors.New("eof"))),
+			errors.E("app.Handle", get(stderrors.New("eof"))),
+			true,
+		},
+		"chain length": {errors.E("app.Handle", get()), get(), false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.EquivalentTo(tt.a, tt.b); got != tt.want {
+				t.Errorf("EquivalentTo() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
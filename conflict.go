@@ -0,0 +1,41 @@
+package errors
+
+import "fmt"
+
+// maxMismatchValue bounds the length of rendered mismatch values.
+const maxMismatchValue = 256
+
+// Mismatch holds the expected and actual values of a conflict.
+type Mismatch struct {
+	Expected interface{}
+	Actual   interface{}
+}
+
+// Conflict returns a Mismatch which can be passed to E,
+// e.g. Conflict(ifMatch, etag). Errors with a Mismatch are
+// KindConflict unless a kind is set in the chain.
+func Conflict(expected, actual interface{}) Mismatch {
+	return Mismatch{Expected: expected, Actual: actual}
+}
+
+// MismatchOf returns the outermost Mismatch set in err's chain.
+func MismatchOf(err error) (Mismatch, bool) {
+	var m *Mismatch
+	walk(err, func(e *appError) bool {
+		m = e.mismatch
+		return m == nil
+	})
+	if m == nil {
+		return Mismatch{}, false
+	}
+	return *m, true
+}
+
+// renderValue renders v with %v, capped and redacted.
+func renderValue(v interface{}) string {
+	s := []rune(fmt.Sprintf("%v", v))
+	if len(s) > maxMismatchValue {
+		s = append(s[:maxMismatchValue], '…')
+	}
+	return redact(string(s))
+}
@@ -0,0 +1,66 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestConflict(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		kind     errors.KindCode
+		mismatch errors.Mismatch
+		ok       bool
+	}{
+		"conflict": {
+			err:      errors.E("app.Update", errors.Conflict(`"v1"`, `"v2"`)),
+			kind:     errors.KindConflict,
+			mismatch: errors.Mismatch{Expected: `"v1"`, Actual: `"v2"`},
+			ok:       true,
+		},
+		"explicit kind": {
+			err:      errors.E("app.Update", errors.KindPreconditionFailed, errors.Conflict(1, 2)),
+			kind:     errors.KindPreconditionFailed,
+			mismatch: errors.Mismatch{Expected: 1, Actual: 2},
+			ok:       true,
+		},
+		"wrapped kind": {
+			err:      errors.E("app.Update", errors.Conflict(1, 2), errors.E("db.Update", errors.KindGone)),
+			kind:     errors.KindGone,
+			mismatch: errors.Mismatch{Expected: 1, Actual: 2},
+			ok:       true,
+		},
+		"outermost": {
+			err:      errors.E("app.Handle", errors.Conflict("a", "b"), fmt.Errorf("update: %w", errors.E("app.Update", errors.Conflict(1, 2)))),
+			kind:     errors.KindConflict,
+			mismatch: errors.Mismatch{Expected: "a", Actual: "b"},
+			ok:       true,
+		},
+		"none": {
+			err:  errors.E("app.Update", errors.KindNotFound),
+			kind: errors.KindNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.Kind(tt.err); got != tt.kind {
+				t.Errorf("Kind() = %d, want %d", got, tt.kind)
+			}
+			got, ok := errors.MismatchOf(tt.err)
+			if got != tt.mismatch || ok != tt.ok {
+				t.Errorf("MismatchOf() = %v, %t, want %v, %t", got, ok, tt.mismatch, tt.ok)
+			}
+		})
+	}
+}
+
+func TestConflictFormat(t *testing.T) {
+	err := errors.E("app.Update", errors.Conflict(strings.Repeat("a", 300), "b"))
+	want := "expected: " + strings.Repeat("a", 256) + "…, actual: b\n"
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, want) {
+		t.Errorf("%%+v = %q, want containing %q", got, want)
+	}
+}
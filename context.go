@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor extracts a value to be attached to errors from ctx.
+type ContextExtractor func(ctx context.Context) (key string, value interface{}, ok bool)
+
+var contextExtractors struct {
+	sync.RWMutex
+	fns []ContextExtractor
+}
+
+// RegisterContextExtractor registers fn to be consulted by EC.
+func RegisterContextExtractor(fn ContextExtractor) {
+	contextExtractors.Lock()
+	defer contextExtractors.Unlock()
+	contextExtractors.fns = append(contextExtractors.fns, fn)
+}
+
+// EC is like E but also attaches the values extracted from ctx
+// by the registered extractors as fields.
+// Fields passed in args win over extracted values.
+func EC(ctx context.Context, op Op, args ...interface{}) error {
+	fields := Fields{}
+	contextExtractors.RLock()
+	for _, fn := range contextExtractors.fns {
+		if key, value, ok := fn(ctx); ok {
+			fields[key] = value
+		}
+	}
+	contextExtractors.RUnlock()
+
+	pre := []interface{}{fields}
+	if captureGoroutine.Load() {
+		pre = append(pre, contextLabels(ctx))
+	}
+	return newError(1, op, append(pre, args...))
+}
+
+// ContextValue returns the value attached by EC with key
+// from the outermost layer which has it.
+func ContextValue(err error, key string) (interface{}, bool) {
+	v, ok := FieldsOf(err)[key]
+	return v, ok
+}
@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+var maxDepth atomic.Int32
+
+func init() {
+	maxDepth.Store(128)
+}
+
+// SetMaxDepth sets the maximum number of errors visited in a chain.
+// Accessors stop beyond it, and Error, Ops and Msg end with
+// the marker "... more than 128 layers" for the default of 128.
+// Since the traversal is bounded, self-referential chains
+// never hang the accessors.
+func SetMaxDepth(n int) {
+	if n < 1 {
+		n = 1
+	}
+	if n > maxUnwrap {
+		n = maxUnwrap
+	}
+	maxDepth.Store(int32(n))
+}
+
+func depthLimit() int {
+	return int(maxDepth.Load())
+}
+
+// chainLen returns the number of errors in err's tree up to maxUnwrap.
+func chainLen(err error) int {
+	n := 0
+	visitN(err, func(error) bool { return true }, &n, maxUnwrap)
+	return n
+}
+
+// truncation returns the marker of the layers beyond the maximum depth.
+// It does not count them since chains may be self-referential.
+func truncation() string {
+	return "... more than " + strconv.Itoa(depthLimit()) + " layers"
+}
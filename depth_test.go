@@ -0,0 +1,131 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func chain(n int) error {
+	err := errors.E("app.Get", errors.KindNotFound, "no user", errors.NoStack)
+	for i := 1; i < n; i++ {
+		err = errors.E("app.Retry", err, errors.NoStack)
+	}
+	return err
+}
+
+func TestMaxDepth(t *testing.T) {
+	err := chain(200)
+
+	if got, want := errors.Msg(err), "Internal Server Error: ... more than 128 layers"; got != want {
+		t.Errorf("Msg() = %q, want %q", got, want)
+	}
+	ops := errors.Ops(err)
+	if got := ops[len(ops)-1]; got != "... more than 128 layers" {
+		t.Errorf("last op = %q, want the marker", got)
+	}
+	if got := err.Error(); !strings.HasSuffix(got, "... more than 128 layers") {
+		t.Error("Error() does not end with the marker")
+	}
+	if got := errors.Kind(err); got != errors.KindUnexpected {
+		t.Errorf("Kind() = %d, want the kind beyond the depth ignored", got)
+	}
+
+	errors.SetMaxDepth(256)
+	defer errors.SetMaxDepth(128)
+	if got := errors.Msg(err); got != "no user" {
+		t.Errorf("Msg() = %q, want the whole chain", got)
+	}
+	if got := errors.Kind(err); got != errors.KindNotFound {
+		t.Errorf("Kind() = %d, want %d", got, errors.KindNotFound)
+	}
+}
+
+// TestDeepWrap covers 40,000 retries wrapping the same error,
+// which made every accessor walk the whole chain.
+func TestDeepWrap(t *testing.T) {
+	err := chain(40001)
+	const marker = "... more than 128 layers"
+
+	if got := err.Error(); !strings.HasSuffix(got, ": "+marker) {
+		t.Errorf("Error() = ...%q, want the marker last", got[len(got)-40:])
+	}
+	if got, want := errors.Msg(err), "Internal Server Error: "+marker; got != want {
+		t.Errorf("Msg() = %q, want %q", got, want)
+	}
+	ops := errors.Ops(err)
+	if len(ops) != 129 || ops[128] != marker {
+		t.Errorf("Ops() has %d ops ending with %q, want 128 ops and the marker", len(ops), ops[len(ops)-1])
+	}
+	if got := errors.Depth(err); got != 1<<10 {
+		t.Errorf("Depth() = %d, want the bound", got)
+	}
+	if got := errors.Kind(err); got != errors.KindUnexpected {
+		t.Errorf("Kind() = %d, want the kind beyond the depth ignored", got)
+	}
+}
+
+type cyclicError struct{}
+
+func (err *cyclicError) Error() string { return "cyclic" }
+
+func (err *cyclicError) Unwrap() error { return err }
+
+func TestCycle(t *testing.T) {
+	err := errors.E("app.Get", errors.KindNotFound, &cyclicError{})
+
+	if got := errors.Kind(err); got != errors.KindNotFound {
+		t.Errorf("Kind() = %d, want %d", got, errors.KindNotFound)
+	}
+	if got, want := errors.Msg(err), "Not Found: ... more than 128 layers"; got != want {
+		t.Errorf("Msg() = %q, want %q", got, want)
+	}
+	if got := errors.Depth(err); got != 1<<10 {
+		t.Errorf("Depth() = %d, want the bound", got)
+	}
+	if ops := errors.Ops(err); ops[len(ops)-1] != "... more than 128 layers" {
+		t.Errorf("Ops() = %v, want the marker last", ops)
+	}
+	_ = errors.Level(err)
+	_ = errors.Frames(err)
+
+	if got := errors.Kind(errors.E("app.Get", &cyclicError{})); got != errors.KindUnexpected {
+		t.Errorf("Kind() = %d, want %d", got, errors.KindUnexpected)
+	}
+}
+
+func TestCycleEncoding(t *testing.T) {
+	const marker = "... more than 128 layers"
+	tests := map[string]error{
+		"wrapped": errors.E("app.Get", errors.KindNotFound, &cyclicError{}),
+		"plain":   &cyclicError{},
+	}
+	for name, err := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, jerr := errors.MarshalJSON(err)
+			if jerr != nil {
+				t.Fatalf("MarshalJSON() error = %v", jerr)
+			}
+			if !strings.Contains(string(data), marker) {
+				t.Errorf("MarshalJSON() does not contain the marker")
+			}
+
+			data, eerr := errors.Encode(err)
+			if eerr != nil {
+				t.Fatalf("Encode() error = %v", eerr)
+			}
+			if !strings.Contains(string(data), marker) {
+				t.Errorf("Encode() does not contain the marker")
+			}
+			if _, derr := errors.Decode(data); derr != nil {
+				t.Errorf("Decode() error = %v", derr)
+			}
+
+			if got := fmt.Sprintf("%+v", errors.E("app.Handle", err)); !strings.HasSuffix(got, marker) {
+				t.Errorf("%%+v = ...%q, want the marker last", got[len(got)-40:])
+			}
+		})
+	}
+}
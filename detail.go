@@ -0,0 +1,21 @@
+package errors
+
+// Detail is a human-readable string explicitly safe for clients.
+// It can be passed to E repeatedly and is rendered apart from
+// messages by WriteProblem and JSON marshaling.
+type Detail string
+
+// DetailsOf returns the details set in err's chain from outermost
+// to innermost. Identical consecutive details are deduplicated.
+func DetailsOf(err error) []string {
+	var details []string
+	walk(err, func(e *appError) bool {
+		for _, d := range e.details {
+			if len(details) == 0 || details[len(details)-1] != string(d) {
+				details = append(details, string(d))
+			}
+		}
+		return true
+	})
+	return details
+}
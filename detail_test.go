@@ -0,0 +1,49 @@
+package errors_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestDetailsOf(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want []string
+	}{
+		"none":     {errors.E("app.Get", errors.KindNotFound), nil},
+		"repeated": {errors.E("app.Get", errors.Detail("check the id"), errors.Detail("retry later")), []string{"check the id", "retry later"}},
+		"layers": {
+			errors.E("app.Handle", errors.Detail("retry later"),
+				fmt.Errorf("get: %w", errors.E("app.Get", errors.Detail("check the id")))),
+			[]string{"retry later", "check the id"},
+		},
+		"deduplicated": {
+			errors.E("app.Handle", errors.Detail("retry later"),
+				errors.E("app.Get", errors.Detail("retry later"), errors.Detail("check the id"), errors.Detail("retry later"))),
+			[]string{"retry later", "check the id", "retry later"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.DetailsOf(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetailsOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetailRendered(t *testing.T) {
+	err := errors.E("app.Get", errors.KindServiceUnavailable, "dial db-1.internal", errors.Detail("retry later"))
+
+	if got := errors.ProblemDetails(err).Extensions["details"]; !reflect.DeepEqual(got, []string{"retry later"}) {
+		t.Errorf("ProblemDetails() details = %v, want [retry later]", got)
+	}
+	b, _ := errors.MarshalJSON(err)
+	if !strings.Contains(string(b), `"details":["retry later"]`) {
+		t.Errorf("MarshalJSON() = %s, want the details", b)
+	}
+}
@@ -0,0 +1,67 @@
+// Package echoerrors provides an Echo error handler
+// which understands errors of go.nownabe.dev/errors.
+package echoerrors // import "go.nownabe.dev/errors/echoerrors"
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.nownabe.dev/errors"
+)
+
+const op errors.Op = "echoerrors.ErrorHandler"
+
+// ErrorHandler returns an echo.HTTPErrorHandler which writes the status
+// from the error's kind and a JSON body with the client message,
+// and logs the error with logger at its level.
+// *echo.HTTPError is converted into an error of go.nownabe.dev/errors.
+func ErrorHandler(logger errors.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		err = convert(err)
+		errors.LogContext(c.Request().Context(), logger, err)
+
+		if c.Response().Committed {
+			return
+		}
+
+		status := int(errors.Kind(err))
+		if c.Request().Method == http.MethodHead {
+			_ = c.NoContent(status)
+			return
+		}
+		_ = c.JSON(status, map[string]string{"message": message(err)})
+	}
+}
+
+func convert(err error) error {
+	var e errors.Error
+	if stderrors.As(err, &e) {
+		return err
+	}
+	var he *echo.HTTPError
+	if !stderrors.As(err, &he) {
+		return err
+	}
+
+	args := []interface{}{errors.KindCode(he.Code)}
+	if msg, ok := he.Message.(string); ok {
+		args = append(args, msg)
+	} else if he.Message != nil {
+		args = append(args, fmt.Sprint(he.Message))
+	}
+	if he.Internal != nil {
+		args = append(args, he.Internal)
+	}
+	return errors.E(op, args...)
+}
+
+// message returns the client message, hiding it for server errors.
+func message(err error) string {
+	kind := errors.Kind(err)
+	if kind >= 500 {
+		return kind.String()
+	}
+	return errors.ClientMsg(err)
+}
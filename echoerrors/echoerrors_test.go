@@ -0,0 +1,92 @@
+package echoerrors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/errors/echoerrors"
+	"go.nownabe.dev/log"
+)
+
+type logger struct {
+	levels []log.Level
+}
+
+func (l *logger) Log(level log.Level, msg string, keysAndValues ...interface{}) {
+	l.levels = append(l.levels, level)
+}
+
+func TestErrorHandler(t *testing.T) {
+	tests := map[string]struct {
+		method string
+		err    error
+		status int
+		body   string
+	}{
+		"client error": {
+			method: http.MethodGet,
+			err:    errors.E("app.Get", errors.KindNotFound, "no user"),
+			status: http.StatusNotFound,
+			body:   `{"message":"no user (E-`,
+		},
+		"server error": {
+			method: http.MethodGet,
+			err:    errors.E("app.Get", "connection refused"),
+			status: http.StatusInternalServerError,
+			body:   `{"message":"Internal Server Error"}`,
+		},
+		"echo error": {
+			method: http.MethodGet,
+			err:    echo.NewHTTPError(http.StatusBadRequest, "bad id"),
+			status: http.StatusBadRequest,
+			body:   `{"message":"bad id (E-`,
+		},
+		"head": {
+			method: http.MethodHead,
+			err:    errors.E("app.Get", errors.KindNotFound, "no user"),
+			status: http.StatusNotFound,
+			body:   "",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var l logger
+			e := echo.New()
+			e.HTTPErrorHandler = echoerrors.ErrorHandler(&l)
+			e.Any("/users/1", func(c echo.Context) error { return tt.err })
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tt.method, "/users/1", nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Body.String(); !strings.HasPrefix(got, tt.body) || (tt.body == "" && got != "") {
+				t.Errorf("body = %s, want %s...", got, tt.body)
+			}
+			if len(l.levels) != 1 {
+				t.Errorf("logged %d times, want 1", len(l.levels))
+			}
+		})
+	}
+}
+
+func TestErrorHandlerRoute(t *testing.T) {
+	var l logger
+	e := echo.New()
+	e.HTTPErrorHandler = echoerrors.ErrorHandler(&l)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if got := rec.Body.String(); !strings.HasPrefix(got, `{"message":"Not Found (E-`) {
+		t.Errorf("body = %s, want the message of echo.ErrNotFound", got)
+	}
+}
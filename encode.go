@@ -0,0 +1,183 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"sync/atomic"
+
+	"go.nownabe.dev/log"
+)
+
+const wireVersion = 1
+
+type wireEnvelope struct {
+	Version    int        `json:"v"`
+	Service    string     `json:"service,omitempty"`
+	AppVersion string     `json:"app_version,omitempty"`
+	Host       string     `json:"host,omitempty"`
+	Error      *wireError `json:"error"`
+}
+
+type wireError struct {
+	Op        Op           `json:"op,omitempty"`
+	Kind      KindCode     `json:"kind,omitempty"`
+	Level     int          `json:"level,omitempty"`
+	Msg       string       `json:"msg,omitempty"`
+	Origin    string       `json:"origin,omitempty"`
+	Sensitive bool         `json:"sensitive,omitempty"`
+	Fields    Fields       `json:"fields,omitempty"`
+	Frames    []wireFrame  `json:"frames,omitempty"`
+	Error     *string      `json:"error,omitempty"`
+	Cause     *wireError   `json:"cause,omitempty"`
+	Causes    []*wireError `json:"causes,omitempty"`
+}
+
+type wireFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// remoteError is an error decoded from an error not constructed by E.
+type remoteError struct {
+	msg   string
+	cause error
+}
+
+func (err *remoteError) Error() string { return err.msg }
+
+func (err *remoteError) Unwrap() error { return err.cause }
+
+var serviceName atomic.Value
+
+// SetServiceName sets the name of this service stamped by Encode.
+// Decode records it as the origin of the layers created here.
+func SetServiceName(name string) {
+	serviceName.Store(name)
+}
+
+func service() string {
+	name, _ := serviceName.Load().(string)
+	return name
+}
+
+// OriginOf returns the service where the innermost layer with an origin
+// was created. Only errors decoded by Decode have origins.
+func OriginOf(err error) (string, bool) {
+	var origin string
+	walk(err, func(e *appError) bool {
+		if e.origin != "" {
+			origin = e.origin
+		}
+		return true
+	})
+	return origin, origin != ""
+}
+
+// Encode encodes err into a stable JSON schema which preserves
+// ops, kinds, levels, messages, fields, stack frames, origins
+// and sensitivity of the chain so that it can be decoded by Decode in another process.
+// The envelope carries the build info set by SetBuildInfo.
+func Encode(err error) ([]byte, error) {
+	svc, version, host := buildOf(err)
+	return json.Marshal(wireEnvelope{
+		Version:    wireVersion,
+		Service:    svc,
+		AppVersion: version,
+		Host:       host,
+		Error:      newWireError(err, new(int)),
+	})
+}
+
+// newWireError encodes err's chain counting the encoded errors in n.
+// Beyond the maximum depth, the chain is cut with the marker.
+func newWireError(err error, n *int) *wireError {
+	if err == nil {
+		return nil
+	}
+	if *n >= depthLimit() {
+		marker := truncation()
+		return &wireError{Error: &marker}
+	}
+	*n++
+	if m, ok := err.(interface{ Unwrap() []error }); ok {
+		msg := err.Error()
+		w := &wireError{Error: &msg}
+		for _, b := range m.Unwrap() {
+			w.Causes = append(w.Causes, newWireError(b, n))
+		}
+		return w
+	}
+	e, ok := err.(*appError)
+	if !ok {
+		msg := err.Error()
+		return &wireError{Error: &msg, Cause: newWireError(stderrors.Unwrap(err), n)}
+	}
+
+	w := &wireError{
+		Op:     e.op,
+		Kind:   e.kind,
+		Level:  int(e.level),
+		Msg:    e.msg,
+		Origin: e.origin,
+		Fields: e.fields,
+		Cause:  newWireError(e.err, n),
+
+		Sensitive: e.sensitivity == Sensitive,
+	}
+	for _, fr := range e.callers() {
+		w.Frames = append(w.Frames, wireFrame{Function: fr.Function, File: fr.File, Line: fr.Line})
+	}
+	return w
+}
+
+// Decode decodes an error encoded by Encode. Decoded errors have no
+// program counters, but their recorded frames are returned by
+// Frames and Stacktrace. Layers created by the encoding service
+// get its name as their origin.
+func Decode(data []byte) (error, error) {
+	var env wireEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, E("errors.Decode", err, KindBadRequest)
+	}
+	return env.Error.decode(env.Service), nil
+}
+
+func (w *wireError) decode(origin string) error {
+	if w == nil {
+		return nil
+	}
+	if len(w.Causes) > 0 {
+		errs := make([]error, 0, len(w.Causes))
+		for _, c := range w.Causes {
+			if err := c.decode(origin); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return &joinError{errs: errs}
+	}
+	if w.Error != nil {
+		return &remoteError{msg: *w.Error, cause: w.Cause.decode(origin)}
+	}
+
+	e := &appError{
+		op:     w.Op,
+		kind:   w.Kind,
+		level:  log.Level(w.Level),
+		msg:    w.Msg,
+		origin: w.Origin,
+		fields: w.Fields,
+		err:    w.Cause.decode(origin),
+	}
+	if w.Sensitive {
+		e.sensitivity = Sensitive
+	}
+	if e.origin == "" {
+		e.origin = origin
+	}
+	e.decodedFrames = []Frame{}
+	for _, fr := range w.Frames {
+		e.decodedFrames = append(e.decodedFrames, Frame{Function: fr.Function, File: fr.File, Line: fr.Line})
+	}
+	return e
+}
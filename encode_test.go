@@ -0,0 +1,98 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func roundTrip(t *testing.T, err error) error {
+	t.Helper()
+	data, eerr := errors.Encode(err)
+	if eerr != nil {
+		t.Fatalf("Encode() error = %v", eerr)
+	}
+	decoded, derr := errors.Decode(data)
+	if derr != nil {
+		t.Fatalf("Decode() error = %v", derr)
+	}
+	return decoded
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	root := stderrors.New("connection refused")
+	tests := map[string]error{
+		"leaf": errors.E("app.Get", errors.KindNotFound, log.LevelInfo, "no user"),
+		"nested with plain root": errors.E("app.Handle", "handle",
+			fmt.Errorf("query: %w", errors.E("app.Get", errors.KindServiceUnavailable, log.LevelCritical, errors.Fields{"table": "users"}, root))),
+		"joined": errors.E("app.Handle", "handle",
+			errors.E("app.Get", errors.KindNotFound, "no user"), errors.E("app.List", errors.KindServiceUnavailable, root)),
+	}
+	for name, err := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := roundTrip(t, err)
+
+			if g, w := errors.Kind(got), errors.Kind(err); g != w {
+				t.Errorf("Kind() = %d, want %d", g, w)
+			}
+			if !errors.Is(got, errors.Kind(err)) {
+				t.Errorf("Is(%d) = false, want true", errors.Kind(err))
+			}
+			if g, w := errors.Ops(got), errors.Ops(err); !reflect.DeepEqual(g, w) {
+				t.Errorf("Ops() = %v, want %v", g, w)
+			}
+			if g, w := errors.Msg(got), errors.Msg(err); g != w {
+				t.Errorf("Msg() = %q, want %q", g, w)
+			}
+			if g, w := errors.Level(got), errors.Level(err); g != w {
+				t.Errorf("Level() = %v, want %v", g, w)
+			}
+			if g, w := got.Error(), err.Error(); g != w {
+				t.Errorf("Error() = %q, want %q", g, w)
+			}
+			if g, w := errors.FieldsOf(got), errors.FieldsOf(err); !reflect.DeepEqual(g, w) {
+				t.Errorf("FieldsOf() = %v, want %v", g, w)
+			}
+			if g, w := errors.Stacktrace(got), errors.Stacktrace(err); len(w) == 0 || !reflect.DeepEqual(g, w) {
+				t.Errorf("Stacktrace() = %v, want %v", g, w)
+			}
+		})
+	}
+}
+
+func TestEncodeOrigin(t *testing.T) {
+	errors.SetServiceName("users")
+	defer errors.SetServiceName("")
+
+	got := roundTrip(t, errors.E("app.Get", errors.KindNotFound))
+	if origin, ok := errors.OriginOf(got); !ok || origin != "users" {
+		t.Errorf("OriginOf() = %q, %v, want users", origin, ok)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	_, err := errors.Decode([]byte("{"))
+	if !errors.Is(err, errors.KindBadRequest) {
+		t.Errorf("Decode() error = %v, want KindBadRequest", err)
+	}
+}
+
+func TestEncodeSensitive(t *testing.T) {
+	err := errors.E("app.Handle", "handle",
+		errors.E("app.Get", errors.KindNotFound, errors.Sensitive, "no user alice@example.com"))
+	got := roundTrip(t, err)
+
+	if got := errors.Msg(got); got != "Not Found" {
+		t.Errorf("Msg() = %q, want the kind text", got)
+	}
+	for name, msg := range map[string]string{"Msg": errors.Msg(got), "ClientMsg": errors.ClientMsg(got)} {
+		if strings.Contains(msg, "alice") {
+			t.Errorf("%s() = %q, want the message hidden", name, msg)
+		}
+	}
+}
@@ -1,192 +1,918 @@
 package errors // import "go.nownabe.dev/errors"
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"net/http"
+	"reflect"
 	"runtime"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.nownabe.dev/log"
 	"golang.org/x/xerrors"
 )
 
+// KindCode classifies errors. Its values are HTTP status codes.
+// The type is named KindCode because Kind is taken by the accessor.
+type KindCode int
+
 const (
 	// KindBadRequest is a kind.
-	KindBadRequest = http.StatusBadRequest
+	KindBadRequest KindCode = http.StatusBadRequest
 	// KindUnauthorized is a kind.
-	KindUnauthorized = http.StatusUnauthorized
+	KindUnauthorized KindCode = http.StatusUnauthorized
 	// KindForbidden is a kind.
-	KindForbidden = http.StatusForbidden
+	KindForbidden KindCode = http.StatusForbidden
 	// KindNotFound is a kind.
-	KindNotFound = http.StatusNotFound
+	KindNotFound KindCode = http.StatusNotFound
+	// KindConflict is a kind.
+	KindConflict KindCode = http.StatusConflict
+	// KindGone is a kind.
+	KindGone KindCode = http.StatusGone
+	// KindPreconditionFailed is a kind.
+	KindPreconditionFailed KindCode = http.StatusPreconditionFailed
+	// KindUnprocessableEntity is a kind.
+	KindUnprocessableEntity KindCode = http.StatusUnprocessableEntity
+	// KindTooManyRequests is a kind.
+	KindTooManyRequests KindCode = http.StatusTooManyRequests
+	// KindClientClosedRequest is a kind of requests canceled by clients.
+	KindClientClosedRequest KindCode = 499
 	// KindUnexpected is a kind.
-	KindUnexpected = http.StatusInternalServerError
+	KindUnexpected KindCode = http.StatusInternalServerError
+	// KindNotImplemented is a kind.
+	KindNotImplemented KindCode = http.StatusNotImplemented
+	// KindServiceUnavailable is a kind.
+	KindServiceUnavailable KindCode = http.StatusServiceUnavailable
+	// KindGatewayTimeout is a kind.
+	KindGatewayTimeout KindCode = http.StatusGatewayTimeout
 )
 
+var kindTexts = struct {
+	sync.RWMutex
+	m map[KindCode]string
+}{m: map[KindCode]string{}}
+
+// RegisterKind registers the text of a custom kind.
+// It can also override the text of HTTP status codes.
+func RegisterKind(code KindCode, text string) {
+	kindTexts.Lock()
+	defer kindTexts.Unlock()
+	kindTexts.m[code] = text
+}
+
+func registeredKind(k KindCode) bool {
+	kindTexts.RLock()
+	defer kindTexts.RUnlock()
+	_, ok := kindTexts.m[k]
+	return ok
+}
+
+// String returns the text of the kind, e.g. "Not Found".
+// Unregistered non-HTTP kinds are rendered as "Unknown Kind (1001)".
+func (k KindCode) String() string {
+	kindTexts.RLock()
+	text, ok := kindTexts.m[k]
+	kindTexts.RUnlock()
+	if ok {
+		return text
+	}
+	if text := http.StatusText(int(k)); text != "" {
+		return text
+	}
+	if k == KindClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return "Unknown Kind (" + strconv.Itoa(int(k)) + ")"
+}
+
 // Op describes packages and functions.
 type Op string
 
+// Error is implemented by errors constructed by E.
+// Its accessors return the values of the single layer
+// rather than the values aggregated over the chain,
+// so that it can be extracted with errors.As.
+type Error interface {
+	error
+	Op() Op
+	Kind() KindCode
+	Level() log.Level
+	Msg() string
+	Unwrap() error
+}
+
 type appError struct {
 	err    error
 	msg    string
 	op     Op
-	kind   int
+	kind   KindCode
 	level  log.Level
-	frames [3]uintptr
+	fields Fields
+	frames []uintptr
+
+	transience Transience
+	retryAfter time.Duration
+	template   *Template
+	stack      StackMode
+
+	fieldErrors FieldErrors
+	forceLevel  bool
+	batch       []batchItem
+	sensitivity Sensitivity
+	msgKey      *LocalizedMessage
+	derivedOp   bool
+	created     time.Time
+	user        User
+	category    InCategory
+	headers     []HTTPHeader
+	code        Code
+	resource    *ResourceRef
+	origin      string
+	goroutine   *goroutineInfo
+	mismatch    *Mismatch
+	related     []error
+	details     []Detail
+	args        ArgValues
+
+	// reclassifiedFrom is the kind replaced by Reclassify.
+	reclassifiedFrom KindCode
+
+	// decodedFrames are the frames of errors decoded by Decode.
+	decodedFrames []Frame
 }
 
 // E constructs an error.
+// Nil errors in args, including typed nil pointers, are ignored.
+// If op is empty, it is derived from the name of the calling function
+// like "pkg.Func" unless the stack is not captured.
+// When multiple errors are passed, all of them are wrapped
+// and the chain branches. Accessors traverse the branches
+// in depth-first order.
 func E(op Op, args ...interface{}) error {
-	e := &appError{op: op}
-	runtime.Callers(1, e.frames[:])
+	return newError(1, op, args)
+}
+
+// ECaller is like E but attributes the location to a caller
+// skip levels above the caller of ECaller. It is intended for
+// helpers which construct errors on behalf of their callers.
+// ECaller(0, ...) is equivalent to E.
+func ECaller(skip int, op Op, args ...interface{}) error {
+	return newError(skip+1, op, args)
+}
+
+// newError constructs an error whose location is the caller
+// skip levels above the caller of newError.
+func newError(skip int, op Op, args []interface{}) *appError {
+	e := newAppError(op)
+	errs, wrapped := e.set(args)
+	e.finish(skip+1, errs, wrapped)
+	notify(e)
+	return e
+}
+
+// E2 is like E(op, err, msg, kind) but avoids the allocations
+// of variadic arguments for the common case of wrapping.
+func E2(op Op, err error, msg string, kind KindCode) error {
+	e := newAppError(op)
+	e.msg = msg
+	e.kind = kind
+	if !isNil(err) {
+		e.err = err
+	}
+	e.finish(1, nil, nil)
+	notify(e)
+	return e
+}
 
+func newAppError(op Op) *appError {
+	e := &appError{op: op, created: now()}
+	if captureGoroutine.Load() {
+		e.goroutine = &goroutineInfo{id: goroutineID()}
+	}
+	return e
+}
+
+// finish completes the construction of e whose location is the caller
+// skip levels above the caller of finish. Constructors call notify
+// once they have set all the fields of e.
+func (e *appError) finish(skip int, errs []error, wrapped error) {
+	if e.captures() {
+		e.frames = make([]uintptr, stackDepth())
+		e.frames = e.frames[:runtime.Callers(skip+2, e.frames)]
+	}
+	if e.op != "" && strict.Load() {
+		if err := e.op.Validate(); err != nil {
+			panic(err)
+		}
+	}
+	if e.op == "" {
+		if fr, ok := e.location(); ok {
+			e.op = funcOp(fr.Function)
+			e.derivedOp = true
+		}
+	}
+	e.wrap(errs, wrapped)
+	if e.mismatch != nil && e.kind == 0 {
+		if _, ok := KindExplicit(e.err); !ok {
+			e.kind = KindConflict
+		}
+	}
+}
+
+// set applies args other than errors to e
+// and returns the errors to be wrapped.
+func (e *appError) set(args []interface{}) (errs []error, wrapped error) {
+	b := builder{e: e}
 	for _, a := range args {
-		switch a := a.(type) {
-		case error:
-			e.err = a
-		case string:
-			e.msg = a
-		case log.Level:
-			e.level = a
-		case int:
-			e.kind = a
+		b.add(a)
+	}
+	return b.errs, b.wrapped
+}
+
+// builder accumulates arguments of E and options of Build.
+type builder struct {
+	e       *appError
+	errs    []error
+	wrapped error
+}
+
+func (b *builder) add(a interface{}) {
+	e := b.e
+	switch a := a.(type) {
+	case error:
+		if !isNil(a) {
+			b.errs = append(b.errs, a)
+		}
+	case string:
+		e.msg = a
+	case Message:
+		e.msg = a.text
+		b.wrapped = a.wrapped
+	case LocalizedMessage:
+		e.msg = a.resolve(defaultLanguage())
+		e.msgKey = &a
+	case log.Level:
+		e.level = a
+		e.forceLevel = false
+	case ForceLevel:
+		e.level = log.Level(a)
+		e.forceLevel = true
+	case Fields:
+		e.fields = e.fields.merge(a)
+	case FieldErrors:
+		e.fieldErrors = FieldErrors(nil).merge(e.fieldErrors).merge(a)
+	case StackMode:
+		e.stack = a
+	case Transience:
+		e.transience = a
+	case Sensitivity:
+		e.sensitivity = a
+	case ArgValues:
+		e.args = append(e.args, a...)
+	case Detail:
+		e.details = append(e.details, a)
+	case User:
+		e.user = a
+	case InCategory:
+		e.category = a
+	case Code:
+		e.code = a
+	case ResourceRef:
+		e.resource = &a
+	case Mismatch:
+		e.mismatch = &a
+	case RelatedErrors:
+		for _, r := range a {
+			if !isNil(r) {
+				e.related = append(e.related, r)
+			}
 		}
+	case goroutineLabels:
+		if e.goroutine != nil {
+			e.goroutine.labels = a
+		}
+	case HTTPHeader:
+		e.headers = append(e.headers, a)
+		if a.retryAfter > 0 {
+			e.retryAfter = a.retryAfter
+		}
+	case time.Duration:
+		e.retryAfter = a
+	case KindCode:
+		e.kind = a
+	case int:
+		// Deprecated: plain int kinds are accepted for compatibility
+		// and will be removed in a future release. Use KindCode.
+		e.kind = KindCode(a)
+	case nil:
+	default:
+		if strict.Load() {
+			panic(fmt.Sprintf("errors: unsupported argument of type %T passed to E", a))
+		}
+		e.fields = e.fields.merge(Fields{fmt.Sprintf("%T", a): a})
 	}
+}
 
-	if e.err == nil {
-		e.err = New(string(op))
+func (e *appError) wrap(errs []error, wrapped error) {
+	switch {
+	case len(errs) == 1:
+		e.err = errs[0]
+	case len(errs) > 1:
+		e.err = &joinError{errs: errs}
+	case wrapped != nil:
+		e.err = wrapped
 	}
+}
 
-	return e
+// New constructs a leaf error with the message.
+// args are options accepted by E such as kinds and levels.
+func New(op Op, msg string, args ...interface{}) error {
+	return newError(1, op, append([]interface{}{msg}, args...))
 }
 
-// New constructs a new error.
-func New(msg string) error {
-	return fmt.Errorf(msg)
+// Newf constructs a leaf error with the message formatted
+// like fmt.Sprintf. Use E with Msgf to pass options.
+func Newf(op Op, format string, a ...interface{}) error {
+	return newError(1, op, []interface{}{Msgf(format, a...)})
+}
+
+// Wrap is like E but returns nil if err is nil,
+// including typed nil pointers.
+func Wrap(op Op, err error, args ...interface{}) error {
+	if isNil(err) {
+		return nil
+	}
+	return newError(1, op, append([]interface{}{err}, args...))
+}
+
+// isNil reports whether err is nil or a typed nil.
+func isNil(err error) bool {
+	if err == nil {
+		return true
+	}
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
 }
 
 // Ops aggregates the error's operations
 // with embedded errors.
 func Ops(err error) []string {
-	ops := []string{}
-	for {
-		e, ok := err.(*appError)
-		if !ok {
-			break
-		}
+	// 16 ops cover most chains with a single allocation.
+	ops := make([]string, 0, 16)
+	if walkTruncated(err, func(e *appError) {
 		ops = append(ops, string(e.op))
-		err = e.err
+	}) {
+		ops = append(ops, truncation())
 	}
 	return ops
 }
 
+// OpsString joins the error's operations with sep.
+// Empty ops and immediately repeated ops are skipped.
+func OpsString(err error, sep string) string {
+	var b strings.Builder
+	var last Op
+	walk(err, func(e *appError) bool {
+		if e.op == "" || e.op == last {
+			return true
+		}
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(string(e.op))
+		last = e.op
+		return true
+	})
+	return b.String()
+}
+
 // Kind returns error's kind.
-func Kind(err error) int {
-	e, ok := err.(*appError)
-	if !ok {
-		return KindUnexpected
-	}
-	if e.kind != 0 {
-		return e.kind
+// If no layer sets a kind, it is KindClientClosedRequest for
+// context.Canceled, KindGatewayTimeout for context.DeadlineExceeded
+// and KindUnexpected otherwise.
+func Kind(err error) KindCode {
+	var p precedence
+	walk(err, p.addKind)
+	kind, _ := p.result(err)
+	return kind
+}
+
+// contextKind returns the kind of context errors in err's chain.
+// Unlike errors.Is, it stops at the maximum depth.
+func contextKind(err error) (kind KindCode, ok bool) {
+	visit(err, func(err error) bool {
+		switch {
+		case isTarget(err, context.Canceled):
+			kind, ok = KindClientClosedRequest, true
+		case isTarget(err, context.DeadlineExceeded):
+			kind, ok = KindGatewayTimeout, true
+		}
+		return !ok
+	})
+	return kind, ok
+}
+
+// isTarget reports whether err matches target without unwrapping err.
+func isTarget(err, target error) bool {
+	if err == target {
+		return true
 	}
+	x, ok := err.(interface{ Is(error) bool })
+	return ok && x.Is(target)
+}
 
-	return Kind(e.err)
+// KindExplicit returns the kind of the outermost layer which sets a kind.
+// ok is false if no layer sets a kind.
+func KindExplicit(err error) (kind KindCode, ok bool) {
+	var p precedence
+	walk(err, p.addKind)
+	return p.kind, p.kindSet
 }
 
 // KindText returns a friendly string of
 // the Kind type.
 func KindText(err error) string {
-	return http.StatusText(Kind(err))
+	return Kind(err).String()
 }
 
+var friendlyKindTexts atomic.Value
+
+// SetKindTexts replaces the texts of kinds for users
+// returned by FriendlyKindText. KindText is not affected.
+func SetKindTexts(texts map[KindCode]string) {
+	m := make(map[KindCode]string, len(texts))
+	for k, v := range texts {
+		m[k] = v
+	}
+	friendlyKindTexts.Store(m)
+}
+
+// FriendlyKindText returns the text of the error's kind for users
+// set by SetKindTexts, falling back to KindText.
+func FriendlyKindText(err error) string {
+	kind := Kind(err)
+	if m, ok := friendlyKindTexts.Load().(map[KindCode]string); ok {
+		if text := m[kind]; text != "" {
+			return text
+		}
+	}
+	return kind.String()
+}
+
+// ForceLevel can be passed to E to set the level
+// which overrides the levels of the wrapped errors.
+// More severe levels of outer layers still take precedence.
+type ForceLevel log.Level
+
 // Level returns error's level.
+// It is the most severe level set in the chain,
+// so that a severe inner error is never masked by outer layers.
+// The outermost level set with ForceLevel takes precedence
+// over the levels beneath it.
+// It defaults to LevelWarn for context errors without kinds
+// and LevelError otherwise if no layer sets a level.
 func Level(err error) log.Level {
-	e, ok := err.(*appError)
-	if !ok {
-		return log.LevelError
+	var p precedence
+	walk(err, p.add)
+	if p.levelSet {
+		return p.level
+	}
+	_, level := p.result(err)
+	return level
+}
+
+// LevelExplicit is like Level but ok is false if no layer sets a level.
+func LevelExplicit(err error) (log.Level, bool) {
+	var p precedence
+	walk(err, p.addLevel)
+	return p.level, p.levelSet
+}
+
+// precedence resolves the kind and the level from the layers
+// added from the outermost. It is shared by Kind, Level and Summary
+// so that they never disagree.
+type precedence struct {
+	kind       KindCode
+	level      log.Level
+	kindSet    bool
+	levelSet   bool
+	levelFixed bool
+}
+
+// add adds the kind and the level of e. It reports whether
+// the layers beneath e may still change either of them.
+func (p *precedence) add(e *appError) bool {
+	more := p.addKind(e)
+	return p.addLevel(e) || more
+}
+
+// addKind adds the kind of e. The outermost kind set wins.
+func (p *precedence) addKind(e *appError) bool {
+	if !p.kindSet && e.kind != 0 {
+		p.kind, p.kindSet = e.kind, true
 	}
+	return !p.kindSet
+}
 
-	if e.level != 0 {
-		return e.level
+// addLevel adds the level of e. The most severe level wins
+// until a level set with ForceLevel, which hides the levels beneath it.
+func (p *precedence) addLevel(e *appError) bool {
+	if p.levelFixed || e.level == 0 {
+		return !p.levelFixed
+	}
+	if !p.levelSet || e.level > p.level {
+		p.level, p.levelSet = e.level, true
 	}
+	p.levelFixed = e.forceLevel
+	return !p.levelFixed
+}
 
-	return Level(e.err)
+// result returns the kind and the level with the defaults for err
+// applied if no layer sets them. Context errors in err's chain
+// select the kind and LevelWarn when no layer sets a kind.
+func (p *precedence) result(err error) (KindCode, log.Level) {
+	kind, level := p.kind, p.level
+	ctx := false
+	if !p.kindSet {
+		kind, ctx = contextKind(err)
+		if !ctx {
+			kind = KindUnexpected
+		}
+	}
+	if !p.levelSet {
+		level = log.LevelError
+		if ctx {
+			level = log.LevelWarn
+		}
+	}
+	return kind, level
 }
 
-// Is checks error's kind.
-func Is(err error, kind int) bool {
+// Is reports whether err's effective kind returned by Kind is kind.
+// Note that errors without kinds, including plain errors,
+// are KindUnexpected. It returns false for nil.
+func Is(err error, kind KindCode) bool {
 	if err == nil {
 		return false
 	}
 	return Kind(err) == kind
 }
 
+// IsKind reports whether any layer in err's chain explicitly sets kind.
+// Unlike Is, errors without kinds never match.
+func IsKind(err error, kind KindCode) bool {
+	found := false
+	if kind == 0 {
+		return false
+	}
+	walk(err, func(e *appError) bool {
+		found = e.kind == kind
+		return !found
+	})
+	return found
+}
+
+// IsNotFound reports whether err's kind is KindNotFound.
+func IsNotFound(err error) bool { return Is(err, KindNotFound) }
+
+// IsBadRequest reports whether err's kind is KindBadRequest.
+func IsBadRequest(err error) bool { return Is(err, KindBadRequest) }
+
+// IsUnauthorized reports whether err's kind is KindUnauthorized.
+func IsUnauthorized(err error) bool { return Is(err, KindUnauthorized) }
+
+// IsForbidden reports whether err's kind is KindForbidden.
+func IsForbidden(err error) bool { return Is(err, KindForbidden) }
+
+// IsUnexpected reports whether err's kind is KindUnexpected.
+func IsUnexpected(err error) bool { return Is(err, KindUnexpected) }
+
+// IsClientError reports whether err's kind is in the range of 400-499.
+func IsClientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	kind := Kind(err)
+	return kind >= 400 && kind <= 499
+}
+
+// IsServerError reports whether err's kind is in the range of 500-599.
+func IsServerError(err error) bool {
+	if err == nil {
+		return false
+	}
+	kind := Kind(err)
+	return kind >= 500 && kind <= 599
+}
+
+// IsErr reports whether any error in err's chain matches target.
+// Unlike Is, it follows the standard library semantics and also
+// descends through errors wrapped by other packages.
+func IsErr(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// MsgOrder is the order in which messages of a chain are joined.
+type MsgOrder int
+
+const (
+	// OuterFirst joins messages from outermost to innermost.
+	OuterFirst MsgOrder = iota
+	// InnerFirst joins messages from innermost to outermost.
+	InnerFirst
+)
+
+type msgJoin struct {
+	sep   string
+	order MsgOrder
+}
+
+var msgJoinConfig atomic.Value
+
+func init() {
+	msgJoinConfig.Store(msgJoin{sep: ": ", order: OuterFirst})
+}
+
+// SetMsgJoin sets the separator and the order used by Msg.
+// They default to ": " and OuterFirst.
+func SetMsgJoin(sep string, order MsgOrder) {
+	msgJoinConfig.Store(msgJoin{sep: sep, order: order})
+}
+
 // Msg returns error message for clients.
+// It joins the messages of the chain with the separator and
+// in the order set by SetMsgJoin. See JoinMsg.
 func Msg(err error) string {
+	c := msgJoinConfig.Load().(msgJoin)
+	return JoinMsg(err, c.sep, c.order)
+}
+
+// MsgChain joins the messages of the chain with sep,
+// outermost first and innermost last. See JoinMsg.
+func MsgChain(err error, sep string) string {
+	return JoinMsg(err, sep, OuterFirst)
+}
+
+// JoinMsg joins the non-empty messages of the chain with sep in order.
+// The messages of the items of a Batch are not included.
+// It falls back to KindText when no layer has a message
+// or a layer is Sensitive, and to Error() when err is not
+// constructed by E. The result is redacted by the redactor
+// and then limited as set by SetMsgLimits.
+func JoinMsg(err error, sep string, order MsgOrder) string {
+	if isSensitive(err) {
+		return KindText(err)
+	}
 	e, ok := err.(*appError)
 	if !ok {
-		return err.Error()
-	}
-
-	var msg string
-	first := true
-	for {
-		if e.msg != "" {
-			if first {
-				msg = e.msg
-				first = false
-			} else {
-				msg = msg + ": " + e.msg
-			}
+		return limitMsg(redact(err.Error()))
+	}
+
+	var b strings.Builder
+	add := func(msg string) {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(msg)
+	}
+
+	// The items of a batch are left to its breakdown,
+	// so the errors beneath a batch layer are skipped.
+	var inner []string
+	if order == InnerFirst {
+		inner = make([]string, 0, 16)
+	}
+	skip := 0
+	n, limit := 0, depthLimit()
+	visitN(e, func(err error) bool {
+		if n > limit {
+			return true
+		}
+		if skip > 0 {
+			skip--
+			return true
 		}
-		e, ok = e.err.(*appError)
+		e, ok := err.(*appError)
 		if !ok {
-			break
+			return true
+		}
+		switch {
+		case e.msg == "":
+		case order == InnerFirst:
+			inner = append(inner, e.msg)
+		default:
+			add(e.msg)
 		}
+		if e.batch != nil {
+			skip = chainLen(e.err)
+		}
+		return true
+	}, &n, limit+1)
+	if len(inner) > 0 {
+		size := len(sep) * len(inner)
+		for _, msg := range inner {
+			size += len(msg)
+		}
+		b.Grow(size)
 	}
-
-	if msg == "" {
-		msg = http.StatusText(Kind(err))
+	for i := len(inner) - 1; i >= 0; i-- {
+		add(inner[i])
+	}
+	if b.Len() == 0 {
+		add(KindText(err))
+	}
+	if n > limit {
+		add(truncation())
 	}
 
-	return msg
+	return limitMsg(redact(b.String()))
 }
 
-func (err *appError) location() (function, file string, line int) {
-	frames := runtime.CallersFrames(err.frames[:])
-	if _, ok := frames.Next(); !ok {
-		return "", "", 0
+// ClientMsg returns only the outermost non-empty message,
+// falling back to "<resource kind> not found" for KindNotFound errors
+// with resources and to FriendlyKindText otherwise. The result is redacted
+// by the redactor and then limited as set by SetMsgLimits.
+// It ends with TicketCode like "Not Found (E-7F3K2)".
+func ClientMsg(err error) string {
+	if err == nil {
+		return ""
 	}
-	fr, ok := frames.Next()
-	if !ok {
-		return "", "", 0
+	return clientMsg(err) + " (" + TicketCode(err) + ")"
+}
+
+func clientMsg(err error) string {
+	if isSensitive(err) {
+		return FriendlyKindText(err)
+	}
+	msg := ""
+	walk(err, func(e *appError) bool {
+		msg = e.msg
+		return msg == ""
+	})
+	if msg != "" {
+		return limitMsg(redact(msg))
 	}
+	if kind, _, ok := ResourceOf(err); ok && Kind(err) == KindNotFound {
+		return kind + " not found"
+	}
+	return FriendlyKindText(err)
+}
 
-	return fr.Function, fr.File, fr.Line
+// Walk visits each error constructed by E in err's chain
+// from outermost to innermost until fn returns false.
+// It descends through other wrappers with errors.Unwrap
+// and through every branch of multiple errors in depth-first order.
+func Walk(err error, fn func(e Error) bool) {
+	walk(err, func(e *appError) bool { return fn(e) })
 }
 
-// Stacktrace returns an array of stacktrace tupples
-// that inclues function, file and line.
-func Stacktrace(err error) [][3]string {
-	frames := [][3]string{}
-	for {
+// walkTruncated calls fn for each error constructed by E in err's tree
+// up to the maximum depth, and reports whether the tree is deeper
+// so that callers can mark the truncated layers.
+func walkTruncated(err error, fn func(e *appError)) bool {
+	n, limit := 0, depthLimit()
+	visitN(err, func(err error) bool {
+		if e, ok := err.(*appError); ok && n <= limit {
+			fn(e)
+		}
+		return true
+	}, &n, limit+1)
+	return n > limit
+}
+
+func walk(err error, fn func(e *appError) bool) {
+	visit(err, func(err error) bool {
 		e, ok := err.(*appError)
+		return !ok || fn(e)
+	})
+}
+
+// visit visits every error in err's tree in depth-first order
+// up to the maximum depth.
+// It returns false if fn stopped the traversal.
+func visit(err error, fn func(err error) bool) bool {
+	n := 0
+	return visitN(err, fn, &n, depthLimit())
+}
+
+// visitN is visit which counts visited errors in n
+// and stops when it reaches limit. Nil errors including typed nils
+// end the branch.
+func visitN(err error, fn func(err error) bool, n *int, limit int) bool {
+	for !isNil(err) && *n < limit {
+		*n++
+		if !fn(err) {
+			return false
+		}
+		if m, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, b := range m.Unwrap() {
+				if !visitN(b, fn, n, limit) {
+					return false
+				}
+			}
+			return true
+		}
+		err = stderrors.Unwrap(err)
+	}
+	return true
+}
+
+var strict atomic.Bool
+
+// SetStrict sets whether E panics on arguments of unsupported types
+// and on ops which fail Op.Validate.
+// It is intended for development. When not strict, such arguments
+// are recorded as fields keyed by their type names.
+func SetStrict(enabled bool) {
+	strict.Store(enabled)
+}
+
+var kindInError atomic.Bool
+
+// SetKindInError sets whether Error includes the kinds of layers.
+func SetKindInError(include bool) {
+	kindInError.Store(include)
+}
+
+// Error returns a single-line rendering of the chain.
+// Each layer is rendered as "op: msg", or "op: 404 Not Found: msg"
+// if enabled by SetKindInError, omitting empty parts, and is
+// followed by ": " and the wrapped error.
+// A leaf error without op and message falls back to its kind text
+// and then to "(no error)".
+func (err *appError) Error() string {
+	withKind := kindInError.Load()
+	limit := depthLimit()
+	var parts []string
+	var cur error = err
+	for i := 0; !isNil(cur); i++ {
+		e, ok := cur.(*appError)
 		if !ok {
+			parts = append(parts, cur.Error())
 			break
 		}
-		function, file, line := e.location()
-		if function != "" && file != "" {
-			frames = append(frames, [3]string{function, file, strconv.Itoa(line)})
+		if i >= limit {
+			parts = append(parts, truncation())
+			break
+		}
+		text := e.text(withKind)
+		switch {
+		case e.err == nil && text != "":
+			parts = append(parts, text)
+		case e.err == nil && e.kind != 0:
+			parts = append(parts, e.kind.String())
+		case e.err == nil:
+			parts = append(parts, "(no error)")
+		case text != "":
+			parts = append(parts, text)
 		}
-		err = e.err
+		cur = e.err
 	}
-	return frames
+	return strings.Join(parts, ": ")
+}
 
+func (err *appError) text(withKind bool) string {
+	parts := make([]string, 0, 3)
+	if err.op != "" {
+		parts = append(parts, string(err.op))
+	}
+	if withKind && err.kind != 0 {
+		parts = append(parts, strconv.Itoa(int(err.kind))+" "+err.kind.String())
+	}
+	if err.msg != "" {
+		parts = append(parts, err.msg)
+	}
+	return strings.Join(parts, ": ")
 }
 
-// Error returns the core error message.
-func (err *appError) Error() string {
-	return err.err.Error()
+// Op returns the operation of this layer.
+func (err *appError) Op() Op {
+	return err.op
+}
+
+// Kind returns the kind set on this layer, or 0.
+func (err *appError) Kind() KindCode {
+	return err.kind
+}
+
+// Level returns the level set on this layer, or 0.
+func (err *appError) Level() log.Level {
+	return err.level
+}
+
+// Msg returns the message set on this layer.
+func (err *appError) Msg() string {
+	return err.msg
 }
 
 // Unwrap returns a wrapped error.
@@ -194,8 +920,18 @@ func (err *appError) Unwrap() error {
 	return err.err
 }
 
-// Format .
-func (err *appError) Format(s fmt.State, v rune) { xerrors.FormatError(err, s, v) }
+// Format implements fmt.Formatter.
+// %+v prints the detailed chain and the other verbs print Error().
+func (err *appError) Format(s fmt.State, v rune) {
+	switch {
+	case v == 'v' && s.Flag('+'):
+		xerrors.FormatError(&boundedFormatter{f: err}, s, v)
+	case v == 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	default:
+		io.WriteString(s, err.Error())
+	}
+}
 
 // FormatError .
 func (err *appError) FormatError(p xerrors.Printer) (next error) {
@@ -205,13 +941,45 @@ func (err *appError) FormatError(p xerrors.Printer) (next error) {
 		p.Print(err.msg)
 	}
 	if p.Detail() {
-		function, file, line := err.location()
-		if function != "" {
-			p.Printf("%s\n    ", function)
+		if err.op != "" {
+			p.Printf("op: %s\n", err.op)
+		}
+		switch {
+		case err.reclassifiedFrom != 0:
+			p.Printf("kind: %d %s (reclassified from %d)\n", int(err.kind), err.kind, int(err.reclassifiedFrom))
+		case err.kind != 0:
+			p.Printf("kind: %d %s\n", int(err.kind), err.kind)
+		}
+		if err.level != 0 {
+			p.Printf("level: %v\n", err.level)
+		}
+		if err.origin != "" {
+			p.Printf("via %s\n", err.origin)
+		}
+		if m := err.mismatch; m != nil {
+			p.Printf("expected: %s, actual: %s\n", renderValue(m.Expected), renderValue(m.Actual))
+		}
+		if err.user != "" {
+			p.Printf("user: %s\n", err.user)
 		}
-		if file != "" {
-			p.Printf("%s:%d\n", file, line)
+		if err.goroutine != nil {
+			p.Printf("goroutine: %s\n", err.goroutine)
+		}
+		if !err.created.IsZero() {
+			p.Printf("created: %s\n", err.created.Format(time.RFC3339Nano))
+		}
+		if fr, ok := err.location(); ok {
+			p.Printf("%s\n", fr)
+		}
+		for _, k := range err.fields.keys() {
+			p.Printf("    %s=%v\n", k, err.fields[k])
+		}
+		if len(err.args) > 0 {
+			p.Printf("    args: %s\n", err.args)
+		}
+		for _, r := range err.related {
+			p.Printf("related: %v\n", r)
 		}
 	}
-	return err.err
+	return foreign(err.err)
 }
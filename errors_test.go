@@ -0,0 +1,389 @@
+package errors_test
+
+import (
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestErrorAs(t *testing.T) {
+	inner := errors.E("app.Get", errors.KindNotFound, log.LevelInfo, "no user")
+	outer := errors.E("app.Handle", "failed", fmt.Errorf("wrapped: %w", inner))
+	err := fmt.Errorf("top: %w", outer)
+
+	var e errors.Error
+	if !stderrors.As(err, &e) {
+		t.Fatal("errors.As returned false")
+	}
+	if e.Op() != "app.Handle" {
+		t.Errorf("Op() = %q, want %q", e.Op(), "app.Handle")
+	}
+	if e.Kind() != 0 {
+		t.Errorf("Kind() = %d, want 0 for a layer without kind", e.Kind())
+	}
+	if e.Level() != 0 {
+		t.Errorf("Level() = %v, want 0 for a layer without level", e.Level())
+	}
+	if e.Msg() != "failed" {
+		t.Errorf("Msg() = %q, want %q", e.Msg(), "failed")
+	}
+
+	var layers []errors.Error
+	errors.Walk(err, func(e errors.Error) bool {
+		layers = append(layers, e)
+		return true
+	})
+	if len(layers) != 2 {
+		t.Fatalf("got %d layers, want 2", len(layers))
+	}
+	in := layers[1]
+	if in.Op() != "app.Get" || in.Kind() != errors.KindNotFound || in.Level() != log.LevelInfo || in.Msg() != "no user" {
+		t.Errorf("inner layer = (%q, %d, %v, %q)", in.Op(), in.Kind(), in.Level(), in.Msg())
+	}
+	if in.Unwrap() != nil {
+		t.Errorf("inner Unwrap() = %v, want nil", in.Unwrap())
+	}
+	if errors.Kind(err) != errors.KindNotFound {
+		t.Errorf("Kind(err) = %d, want the inherited %d", errors.Kind(err), errors.KindNotFound)
+	}
+}
+
+func TestIsErr(t *testing.T) {
+	tests := map[string]struct {
+		err     error
+		wantOps []string
+		wantIs  bool
+	}{
+		"through fmt.Errorf": {
+			errors.E("app.Handle", errors.E("app.List", fmt.Errorf("x: %w", errors.E("app.Query", sql.ErrNoRows)))),
+			[]string{"app.Handle", "app.List", "app.Query"}, true,
+		},
+		"fmt.Errorf outermost": {
+			fmt.Errorf("top: %w", errors.E("app.Handle", errors.E("app.Query", sql.ErrNoRows))),
+			[]string{"app.Handle", "app.Query"}, true,
+		},
+		"other sentinel": {
+			errors.E("app.Handle", fmt.Errorf("x: %w", errors.E("app.Query", io.EOF))),
+			[]string{"app.Handle", "app.Query"}, false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.Ops(tt.err); !reflect.DeepEqual(got, tt.wantOps) {
+				t.Errorf("Ops() = %v, want %v", got, tt.wantOps)
+			}
+			if got := errors.IsErr(tt.err, sql.ErrNoRows); got != tt.wantIs {
+				t.Errorf("IsErr(sql.ErrNoRows) = %v, want %v", got, tt.wantIs)
+			}
+		})
+	}
+}
+
+func TestErrorAsPlain(t *testing.T) {
+	var e errors.Error
+	if stderrors.As(fmt.Errorf("x: %w", stderrors.New("plain")), &e) {
+		t.Error("errors.As returned true for a chain without errors constructed by E")
+	}
+}
+
+func TestLeafError(t *testing.T) {
+	for _, op := range []errors.Op{"", "app.Op"} {
+		for _, msg := range []string{"", "boom"} {
+			for _, kind := range []errors.KindCode{0, errors.KindNotFound} {
+				for _, level := range []log.Level{0, log.LevelWarn} {
+					name := fmt.Sprintf("op=%q/msg=%q/kind=%d/level=%d", op, msg, kind, level)
+					t.Run(name, func(t *testing.T) {
+						args := []interface{}{errors.NoStack}
+						if msg != "" {
+							args = append(args, msg)
+						}
+						if kind != 0 {
+							args = append(args, kind)
+						}
+						if level != 0 {
+							args = append(args, level)
+						}
+						err := errors.E(op, args...)
+
+						want := "(no error)"
+						switch {
+						case op != "" && msg != "":
+							want = string(op) + ": " + msg
+						case op != "":
+							want = string(op)
+						case msg != "":
+							want = msg
+						case kind != 0:
+							want = kind.String()
+						}
+						if got := err.Error(); got != want {
+							t.Errorf("Error() = %q, want %q", got, want)
+						}
+
+						wantMsg := msg
+						if wantMsg == "" {
+							wantMsg = errors.KindText(err)
+						}
+						if got := errors.Msg(err); got != wantMsg {
+							t.Errorf("Msg() = %q, want %q", got, wantMsg)
+						}
+						if got := errors.Ops(err); len(got) != 1 || got[0] != string(op) {
+							t.Errorf("Ops() = %q, want [%q]", got, op)
+						}
+						if got := errors.Stacktrace(err); len(got) != 0 {
+							t.Errorf("Stacktrace() = %v, want empty", got)
+						}
+						if got := stderrors.Unwrap(err); got != nil {
+							t.Errorf("Unwrap() = %v, want nil", got)
+						}
+						if got := fmt.Sprintf("%+v", err); got == "" {
+							t.Error("detailed format is empty")
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestErrorString(t *testing.T) {
+	plain := stderrors.New("connection refused")
+	tests := []struct {
+		name     string
+		err      error
+		want     string
+		wantKind string
+	}{
+		{
+			name:     "leaf",
+			err:      errors.E("app.Get", errors.NoStack, errors.KindNotFound, "no user"),
+			want:     "app.Get: no user",
+			wantKind: "app.Get: 404 Not Found: no user",
+		},
+		{
+			name:     "message-only layers",
+			err:      errors.E("", errors.NoStack, "handle", errors.E("", errors.NoStack, "get user")),
+			want:     "handle: get user",
+			wantKind: "handle: get user",
+		},
+		{
+			name:     "plain wrapped error",
+			err:      errors.E("app.Handle", errors.NoStack, errors.E("app.Get", errors.NoStack, errors.KindUnexpected, plain)),
+			want:     "app.Handle: app.Get: connection refused",
+			wantKind: "app.Handle: app.Get: 500 Internal Server Error: connection refused",
+		},
+		{
+			name:     "foreign wrapper in the middle",
+			err:      errors.E("app.Handle", errors.NoStack, fmt.Errorf("dial: %w", plain)),
+			want:     "app.Handle: dial: connection refused",
+			wantKind: "app.Handle: dial: connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			if got := fmt.Sprintf("%v", tt.err); got != tt.want {
+				t.Errorf("%%v = %q, want %q", got, tt.want)
+			}
+
+			errors.SetKindInError(true)
+			defer errors.SetKindInError(false)
+			if got := tt.err.Error(); got != tt.wantKind {
+				t.Errorf("Error() with kinds = %q, want %q", got, tt.wantKind)
+			}
+		})
+	}
+}
+
+type point struct{ X, Y int }
+
+func TestUnsupportedArgs(t *testing.T) {
+	var nilStringer fmt.Stringer
+
+	err := errors.E("app.Get", point{1, 2}, nilStringer, 3*time.Second)
+	if got := errors.FieldsOf(err)["errors_test.point"]; got != (point{1, 2}) {
+		t.Errorf(`FieldsOf()["errors_test.point"] = %v, want the struct`, got)
+	}
+	if got := len(errors.FieldsOf(err)); got != 1 {
+		t.Errorf("len(FieldsOf()) = %d, want 1", got)
+	}
+	if d, ok := errors.RetryAfter(err); !ok || d != 3*time.Second {
+		t.Errorf("RetryAfter() = %v, %v, want the duration", d, ok)
+	}
+}
+
+func TestUnsupportedArgsStrict(t *testing.T) {
+	errors.SetStrict(true)
+	defer errors.SetStrict(false)
+
+	var nilStringer fmt.Stringer
+	tests := map[string]struct {
+		arg       interface{}
+		wantPanic bool
+	}{
+		"struct":        {point{1, 2}, true},
+		"nil interface": {nilStringer, false},
+		"duration":      {time.Second, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("recover() = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+			_ = errors.E("app.Get", tt.arg)
+		})
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want log.Level
+	}{
+		"warn wrapping critical": {
+			errors.E("app.Handle", log.LevelWarn, errors.E("app.Get", log.LevelCritical)),
+			log.LevelCritical,
+		},
+		"critical wrapping warn": {
+			errors.E("app.Handle", log.LevelCritical, errors.E("app.Get", log.LevelWarn)),
+			log.LevelCritical,
+		},
+		"warn forced over critical": {
+			errors.E("app.Handle", errors.ForceLevel(log.LevelWarn), errors.E("app.Get", log.LevelCritical)),
+			log.LevelWarn,
+		},
+		"critical forced over warn": {
+			errors.E("app.Handle", errors.ForceLevel(log.LevelCritical), errors.E("app.Get", log.LevelWarn)),
+			log.LevelCritical,
+		},
+		"forced under critical": {
+			errors.E("app.Serve", log.LevelCritical, errors.E("app.Handle", errors.ForceLevel(log.LevelWarn), errors.E("app.Get", log.LevelError))),
+			log.LevelCritical,
+		},
+		"forced under info": {
+			errors.E("app.Serve", log.LevelInfo, errors.E("app.Handle", errors.ForceLevel(log.LevelWarn), errors.E("app.Get", log.LevelCritical))),
+			log.LevelWarn,
+		},
+		"forced under fmt wrapper": {
+			fmt.Errorf("serve: %w", errors.E("app.Handle", errors.ForceLevel(log.LevelInfo), errors.E("app.Get", log.LevelError))),
+			log.LevelInfo,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.Level(tt.err); got != tt.want {
+				t.Errorf("Level() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsKind(t *testing.T) {
+	inner := errors.E("app.Get", errors.KindNotFound)
+	tests := map[string]struct {
+		err    error
+		kind   errors.KindCode
+		is     bool
+		isKind bool
+	}{
+		"nil":             {nil, errors.KindUnexpected, false, false},
+		"plain":           {io.EOF, errors.KindUnexpected, true, false},
+		"zero kind":       {errors.E("app.Get", "failed"), 0, false, false},
+		"kindless":        {errors.E("app.Get", "failed"), errors.KindUnexpected, true, false},
+		"explicit":        {inner, errors.KindNotFound, true, true},
+		"inherited":       {errors.E("app.Handle", inner), errors.KindNotFound, true, true},
+		"through wrapper": {fmt.Errorf("handle: %w", inner), errors.KindNotFound, true, true},
+		"masked":          {errors.E("app.Handle", errors.KindBadRequest, inner), errors.KindNotFound, false, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.kind); got != tt.is {
+				t.Errorf("Is() = %v, want %v", got, tt.is)
+			}
+			if got := errors.IsKind(tt.err, tt.kind); got != tt.isKind {
+				t.Errorf("IsKind() = %v, want %v", got, tt.isKind)
+			}
+		})
+	}
+}
+
+func TestJoinMsg(t *testing.T) {
+	err := errors.E("app.Create", "create user",
+		errors.E("app.Save", errors.E("app.Insert", "insert row")))
+	tests := map[string]struct {
+		sep   string
+		order errors.MsgOrder
+		want  string
+	}{
+		"outer first": {": ", errors.OuterFirst, "create user: insert row"},
+		"inner first": {" — ", errors.InnerFirst, "insert row — create user"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.JoinMsg(err, tt.sep, tt.order); got != tt.want {
+				t.Errorf("JoinMsg() = %q, want %q", got, tt.want)
+			}
+
+			errors.SetMsgJoin(tt.sep, tt.order)
+			defer errors.SetMsgJoin(": ", errors.OuterFirst)
+			if got := errors.Msg(err); got != tt.want {
+				t.Errorf("Msg() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type customError struct{}
+
+func (*customError) Error() string { return "custom" }
+
+type mapError map[string]string
+
+func (mapError) Error() string { return "map" }
+
+func TestWrap(t *testing.T) {
+	var typedNil *customError
+	var nilMap mapError
+	tests := map[string]struct {
+		err     error
+		wantNil bool
+	}{
+		"nil":           {nil, true},
+		"typed nil":     {typedNil, true},
+		"typed nil map": {nilMap, true},
+		"error":         {io.EOF, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := errors.Wrap("app.Commit", tt.err, errors.KindConflict)
+			if (err == nil) != tt.wantNil {
+				t.Fatalf("Wrap() = %v, want nil %v", err, tt.wantNil)
+			}
+			if err != nil && !stderrors.Is(err, tt.err) {
+				t.Errorf("Wrap() does not wrap %v", tt.err)
+			}
+		})
+	}
+}
+
+func TestENilErrors(t *testing.T) {
+	var typedNil *customError
+	err := errors.E("app.Commit", "commit", typedNil, error(nil))
+	if got := err.Error(); got != "app.Commit: commit" {
+		t.Errorf("Error() = %q, want the nil errors dropped", got)
+	}
+	if got := stderrors.Unwrap(err); got != nil {
+		t.Errorf("Unwrap() = %v, want nil", got)
+	}
+}
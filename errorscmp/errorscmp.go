@@ -0,0 +1,14 @@
+// Package errorscmp compares errors of go.nownabe.dev/errors
+// with go-cmp in tests.
+package errorscmp // import "go.nownabe.dev/errors/errorscmp"
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"go.nownabe.dev/errors"
+)
+
+// Comparer returns an option which compares errors
+// with errors.EquivalentTo.
+func Comparer() cmp.Option {
+	return cmp.Comparer(errors.EquivalentTo)
+}
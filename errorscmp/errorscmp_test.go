@@ -0,0 +1,47 @@
+package errorscmp_test
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/errors/errorscmp"
+)
+
+type result struct {
+	ID  string
+	Err error
+}
+
+func TestComparer(t *testing.T) {
+	want := result{ID: "u1", Err: errors.E("app.Get", errors.KindNotFound, "no user", stderrors.New("eof"))}
+	tests := map[string]struct {
+		got  result
+		diff bool
+	}{
+		"equal": {
+			got:  result{ID: "u1", Err: errors.E("app.Get", errors.KindNotFound, "no user", errors.NoStack, stderrors.New("eof"))},
+			diff: false,
+		},
+		"kind": {
+			got:  result{ID: "u1", Err: errors.E("app.Get", errors.KindGone, "no user", stderrors.New("eof"))},
+			diff: true,
+		},
+		"chain": {
+			got:  result{ID: "u1", Err: errors.E("app.Get", errors.KindNotFound, "no user", stderrors.New("closed"))},
+			diff: true,
+		},
+		"nil": {
+			got:  result{ID: "u1"},
+			diff: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(want, tt.got, errorscmp.Comparer()); (diff != "") != tt.diff {
+				t.Errorf("cmp.Diff() = %q, want a diff %t", diff, tt.diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+module go.nownabe.dev/errors/errorscmp
+
+require (
+	github.com/google/go-cmp v0.6.0
+	go.nownabe.dev/errors v0.0.0
+)
+
+require (
+	go.nownabe.dev/log v1.0.2 // indirect
+	go.uber.org/atomic v1.4.0 // indirect
+	go.uber.org/multierr v1.1.0 // indirect
+	go.uber.org/zap v1.10.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+)
+
+replace go.nownabe.dev/errors => ../
+
+go 1.23
@@ -0,0 +1,84 @@
+// Package errstest provides test helpers for errors
+// of go.nownabe.dev/errors.
+package errstest // import "go.nownabe.dev/errors/errstest"
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+// AssertNoError fails t if err is not nil,
+// printing err with %+v including the stack.
+func AssertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("unexpected error:\n%+v", err)
+	}
+}
+
+// AssertKind fails t unless err's kind is kind.
+func AssertKind(t testing.TB, err error, kind errors.KindCode) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("got no error, want kind %d %s", int(kind), kind)
+		return
+	}
+	if got := errors.Kind(err); got != kind {
+		t.Errorf("kind mismatch (-want +got):\n- %d %s\n+ %d %s\n\n%+v", int(kind), kind, int(got), got, err)
+	}
+}
+
+// AssertOps fails t unless err's ops are ops.
+func AssertOps(t testing.TB, err error, ops ...errors.Op) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("got no error, want ops %v", ops)
+		return
+	}
+	want := make([]string, len(ops))
+	for i, op := range ops {
+		want[i] = string(op)
+	}
+	got := errors.Ops(err)
+	if d := diff(want, got); d != "" {
+		t.Errorf("ops mismatch (-want +got):\n%s\n%+v", d, err)
+	}
+}
+
+// AssertMsgContains fails t unless errors.Msg of err contains substr.
+func AssertMsgContains(t testing.TB, err error, substr string) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("got no error, want a message containing %q", substr)
+		return
+	}
+	if msg := errors.Msg(err); !strings.Contains(msg, substr) {
+		t.Errorf("message %q does not contain %q\n\n%+v", msg, substr, err)
+	}
+}
+
+// diff returns a line diff of want and got, or "" if they are equal.
+func diff(want, got []string) string {
+	var b strings.Builder
+	equal := len(want) == len(got)
+	for i := 0; i < len(want) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			fmt.Fprintf(&b, "- %q\n", want[i])
+		case i >= len(want):
+			fmt.Fprintf(&b, "+ %q\n", got[i])
+		case want[i] == got[i]:
+			fmt.Fprintf(&b, "  %q\n", want[i])
+		default:
+			equal = false
+			fmt.Fprintf(&b, "- %q\n+ %q\n", want[i], got[i])
+		}
+	}
+	if equal {
+		return ""
+	}
+	return b.String()
+}
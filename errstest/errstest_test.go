@@ -0,0 +1,54 @@
+package errstest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/errors/errstest"
+)
+
+// fakeTB records the failures reported by the assertions.
+type fakeTB struct {
+	testing.TB
+	errs []string
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Errorf(format string, args ...interface{}) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	err := errors.E("app.Handle", errors.E("app.Get", errors.KindNotFound, "no user"))
+	tests := map[string]struct {
+		assert func(t testing.TB)
+		want   string
+	}{
+		"no error":         {func(t testing.TB) { errstest.AssertNoError(t, nil) }, ""},
+		"unexpected error": {func(t testing.TB) { errstest.AssertNoError(t, err) }, "unexpected error:\n(no message):\n    op: app.Handle\n"},
+		"kind":             {func(t testing.TB) { errstest.AssertKind(t, err, errors.KindNotFound) }, ""},
+		"kind mismatch":    {func(t testing.TB) { errstest.AssertKind(t, err, errors.KindGone) }, "kind mismatch (-want +got):\n- 410 Gone\n+ 404 Not Found\n"},
+		"kind of nil":      {func(t testing.TB) { errstest.AssertKind(t, nil, errors.KindGone) }, "got no error, want kind 410 Gone"},
+		"ops":              {func(t testing.TB) { errstest.AssertOps(t, err, "app.Handle", "app.Get") }, ""},
+		"ops mismatch":     {func(t testing.TB) { errstest.AssertOps(t, err, "app.Handle") }, "ops mismatch (-want +got):\n  \"app.Handle\"\n+ \"app.Get\"\n"},
+		"ops of nil":       {func(t testing.TB) { errstest.AssertOps(t, nil, "app.Get") }, "got no error, want ops [app.Get]"},
+		"msg":              {func(t testing.TB) { errstest.AssertMsgContains(t, err, "user") }, ""},
+		"msg mismatch":     {func(t testing.TB) { errstest.AssertMsgContains(t, err, "item") }, `message "no user" does not contain "item"`},
+		"msg of nil":       {func(t testing.TB) { errstest.AssertMsgContains(t, nil, "user") }, `got no error, want a message containing "user"`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tb fakeTB
+			tt.assert(&tb)
+			switch {
+			case tt.want == "" && len(tb.errs) > 0:
+				t.Errorf("failed with %q, want no failure", tb.errs)
+			case tt.want != "" && (len(tb.errs) != 1 || !strings.HasPrefix(tb.errs[0], tt.want)):
+				t.Errorf("failed with %q, want %q", tb.errs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package errors
+
+import "sort"
+
+// Fields are structured key/value pairs attached to an error.
+type Fields map[string]interface{}
+
+func (f Fields) merge(src Fields) Fields {
+	if len(src) == 0 {
+		return f
+	}
+	if f == nil {
+		f = make(Fields, len(src))
+	}
+	for k, v := range src {
+		f[k] = v
+	}
+	return f
+}
+
+func (f Fields) keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FieldsOf returns the fields merged over the error's chain.
+// Outer layers win on key conflicts.
+func FieldsOf(err error) Fields {
+	fields := Fields{}
+	walk(err, func(e *appError) bool {
+		for k, v := range e.fields {
+			if _, ok := fields[k]; !ok {
+				fields[k] = v
+			}
+		}
+		return true
+	})
+	return fields
+}
+
+// FieldErrors are validation errors keyed by field names.
+type FieldErrors map[string][]string
+
+func (f FieldErrors) merge(src FieldErrors) FieldErrors {
+	if len(src) == 0 {
+		return f
+	}
+	if f == nil {
+		f = make(FieldErrors, len(src))
+	}
+	for k, v := range src {
+		f[k] = append(f[k], v...)
+	}
+	return f
+}
+
+func (f FieldErrors) keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FieldErrorsOf returns the field errors merged over the error's chain.
+// Errors of the same field are appended from outermost to innermost.
+func FieldErrorsOf(err error) FieldErrors {
+	var fes FieldErrors
+	walk(err, func(e *appError) bool {
+		fes = fes.merge(e.fieldErrors)
+		return true
+	})
+	return fes
+}
+
+// hasFieldErrors reports whether field errors are rendered for err's kind.
+func hasFieldErrors(err error) (FieldErrors, bool) {
+	switch Kind(err) {
+	case KindBadRequest, KindUnprocessableEntity:
+		fes := FieldErrorsOf(err)
+		return fes, len(fes) > 0
+	}
+	return nil, false
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// Fingerprint returns a stable hash of the error for grouping.
+// It is derived from the ops, the kinds and the function names
+// where the layers were created, so errors from the same code path
+// share the fingerprint regardless of runtime data or line numbers.
+// Chains without layers constructed by E have no code path,
+// so the type and the text of the root error are used instead.
+func Fingerprint(err error) string {
+	return fingerprint(err, Kind(err), false)
+}
+
+// FingerprintWithMsg is like Fingerprint but also includes the
+// outermost message. It is useful when runtime data is kept in
+// fields rather than in messages.
+func FingerprintWithMsg(err error) string {
+	return fingerprint(err, Kind(err), true)
+}
+
+func fingerprint(err error, kind KindCode, withMsg bool) string {
+	h := sha256.New()
+	write := func(s string) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+
+	write(strconv.Itoa(int(kind)))
+	msg := ""
+	layers := 0
+	walk(err, func(e *appError) bool {
+		layers++
+		if msg == "" {
+			msg = e.msg
+		}
+		write(string(e.op))
+		write(strconv.Itoa(int(e.kind)))
+		if fr, ok := e.location(); ok {
+			write(fr.Function)
+		}
+		return true
+	})
+	if layers == 0 && err != nil {
+		root := Root(err)
+		write(fmt.Sprintf("%T", root))
+		write(root.Error())
+	}
+	if withMsg {
+		write(msg)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,100 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func notFound(id int) error {
+	return errors.E("app.Get", errors.KindNotFound, fmt.Sprintf("user %d not found", id), errors.Fields{"id": id})
+}
+
+func notFoundElsewhere(id int) error {
+	return errors.E("app.Get", errors.KindNotFound, fmt.Sprintf("user %d not found", id))
+}
+
+func handle(id int) error {
+	return errors.E("app.Handle", notFound(id))
+}
+
+func TestFingerprintStable(t *testing.T) {
+	want := errors.Fingerprint(handle(0))
+	for i := 1; i < 100; i++ {
+		if got := errors.Fingerprint(handle(i)); got != want {
+			t.Fatalf("Fingerprint() = %s for id %d, want %s", got, i, want)
+		}
+	}
+
+	var wg sync.WaitGroup
+	got := make([]string, 10)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = errors.Fingerprint(handle(i))
+		}()
+	}
+	wg.Wait()
+	for i, fp := range got {
+		if fp != want {
+			t.Errorf("Fingerprint() = %s in goroutine %d, want %s", fp, i, want)
+		}
+	}
+}
+
+func TestFingerprintDistinct(t *testing.T) {
+	base := errors.Fingerprint(notFound(1))
+	others := map[string]error{
+		"function": notFoundElsewhere(1),
+		"kind":     errors.With(notFound(1), errors.KindConflict),
+		"op":       errors.E("app.Handle", notFound(1)),
+	}
+	for name, err := range others {
+		if errors.Fingerprint(err) == base {
+			t.Errorf("Fingerprint() of a different %s is the same", name)
+		}
+	}
+}
+
+func TestFingerprintWithMsg(t *testing.T) {
+	a := errors.E("app.Get", errors.KindNotFound, "user not found", errors.Fields{"id": 1})
+	b := errors.E("app.Get", errors.KindNotFound, "user not found", errors.Fields{"id": 2})
+	c := errors.E("app.Get", errors.KindNotFound, "user was deleted", errors.Fields{"id": 1})
+	if errors.FingerprintWithMsg(a) != errors.FingerprintWithMsg(b) {
+		t.Error("FingerprintWithMsg() differs for the same message")
+	}
+	if errors.Fingerprint(a) != errors.Fingerprint(c) {
+		t.Error("Fingerprint() differs for different messages")
+	}
+	if errors.FingerprintWithMsg(a) == errors.FingerprintWithMsg(c) {
+		t.Error("FingerprintWithMsg() is the same for different messages")
+	}
+}
+
+func TestFingerprintPlain(t *testing.T) {
+	plain := map[string]error{
+		"EOF":    io.EOF,
+		"closed": os.ErrClosed,
+		"path":   &fs.PathError{Op: "open", Path: "/etc/app.yaml", Err: fs.ErrNotExist},
+	}
+	seen := map[string]string{}
+	for name, err := range plain {
+		fp := errors.Fingerprint(err)
+		if other, ok := seen[fp]; ok {
+			t.Errorf("Fingerprint() of %s is the same as of %s", name, other)
+		}
+		seen[fp] = name
+	}
+	if errors.Fingerprint(fmt.Errorf("read: %w", io.EOF)) != errors.Fingerprint(io.EOF) {
+		t.Error("Fingerprint() differs for the same root error")
+	}
+	if errors.TicketCode(io.EOF) == errors.TicketCode(os.ErrClosed) {
+		t.Error("TicketCode() of different plain errors is the same")
+	}
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// foreignError adapts a layer of a chain created elsewhere,
+// typically by fmt.Errorf with %w, so that %+v prints each layer
+// of the chain on its own beneath the layers constructed by E.
+type foreignError struct {
+	err error
+}
+
+// foreign returns err adapted by foreignError if it is a wrapping
+// chain without its own formatting. Otherwise, it returns err as is.
+func foreign(err error) error {
+	switch err.(type) {
+	case nil, *appError, xerrors.Formatter, fmt.Formatter, interface{ Unwrap() []error }:
+		return err
+	}
+	if isNil(stderrors.Unwrap(err)) {
+		return err
+	}
+	return &foreignError{err: err}
+}
+
+func (f *foreignError) Error() string { return f.err.Error() }
+
+func (f *foreignError) Unwrap() error { return f.err }
+
+// FormatError prints the message of this layer without
+// the message of the wrapped error which is printed next.
+func (f *foreignError) FormatError(p xerrors.Printer) (next error) {
+	msg := f.err.Error()
+	next = stderrors.Unwrap(f.err)
+	if inner := next.Error(); inner != "" && strings.HasSuffix(msg, inner) {
+		msg = strings.TrimRight(strings.TrimSuffix(msg, inner), ": ")
+	}
+	p.Print(msg)
+	return foreign(next)
+}
+
+// boundedFormatter counts the layers printed by xerrors.FormatError
+// and ends the chain with the marker beyond the maximum depth
+// so that self-referential chains terminate.
+type boundedFormatter struct {
+	f xerrors.Formatter
+	n int
+}
+
+func (b *boundedFormatter) Error() string { return b.f.Error() }
+
+func (b *boundedFormatter) FormatError(p xerrors.Printer) (next error) {
+	if b.n >= depthLimit() {
+		p.Print(truncation())
+		return nil
+	}
+	next = b.f.FormatError(p)
+	if f, ok := next.(xerrors.Formatter); ok {
+		return &boundedFormatter{f: f, n: b.n + 1}
+	}
+	return next
+}
+
+// framePrinter records the frame printed by xerrors.Frame.Format,
+// which prints the function and then "file:line" in detail mode.
+type framePrinter struct {
+	frame Frame
+}
+
+func (p *framePrinter) Print(args ...interface{}) {}
+
+func (p *framePrinter) Printf(format string, args ...interface{}) {
+	switch format {
+	case "%s\n    ":
+		if len(args) == 1 && p.frame.Function == "" {
+			p.frame.Function, _ = args[0].(string)
+		}
+	case "%s:%d\n":
+		if len(args) == 2 && p.frame.File == "" {
+			p.frame.File, _ = args[0].(string)
+			p.frame.Line, _ = args[1].(int)
+		}
+	}
+}
+
+func (p *framePrinter) Detail() bool { return true }
+
+// foreignFrame returns the frame recorded by err if it follows
+// the convention of xerrors, as errors created by xerrors.Errorf do.
+func foreignFrame(err error) (Frame, bool) {
+	if _, ok := err.(*appError); ok {
+		return Frame{}, false
+	}
+	f, ok := err.(xerrors.Formatter)
+	if !ok {
+		return Frame{}, false
+	}
+	var p framePrinter
+	f.FormatError(&p)
+	if p.frame.File == "" || filtered(p.frame.Function) {
+		return Frame{}, false
+	}
+	return p.frame, true
+}
@@ -0,0 +1,73 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func fixClock(t *testing.T) {
+	t.Helper()
+	errors.SetNow(func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) })
+	t.Cleanup(func() { errors.SetNow(nil) })
+}
+
+func TestFormat(t *testing.T) {
+	fixClock(t)
+	err := errors.E("app.Handle", errors.NoStack, "handle request",
+		errors.E("app.Get", errors.NoStack, errors.KindNotFound, log.LevelWarn, "get user",
+			stderrors.New("sql: no rows in result set")))
+
+	tests := []struct {
+		verb string
+		name string
+	}{
+		{"%v", "format_v"},
+		{"%+v", "format_plus_v"},
+		{"%s", "format_s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.verb, func(t *testing.T) {
+			golden(t, tt.name, fmt.Sprintf(tt.verb, err))
+		})
+	}
+	if got := fmt.Sprintf("%v", err); got != err.Error() {
+		t.Errorf("%%v = %q, want Error() %q", got, err.Error())
+	}
+}
+
+func TestFormatForeign(t *testing.T) {
+	fixClock(t)
+	err := errors.E("app.Handle", errors.NoStack, "handle request",
+		errors.E("app.Get", errors.NoStack, "get user",
+			fmt.Errorf("query: %w", fmt.Errorf("scan: %w", fmt.Errorf("read: %w", io.ErrUnexpectedEOF)))))
+
+	golden(t, "format_foreign_plus_v", fmt.Sprintf("%+v", err))
+}
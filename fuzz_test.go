@@ -0,0 +1,88 @@
+package errors_test
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+// buildChain builds a chain from a program of bytes. Each byte pushes
+// an error built from the errors on the stack.
+func buildChain(program []byte) error {
+	var typedNil *customError
+	stack := []error{io.EOF}
+	pop := func() error {
+		if len(stack) == 0 {
+			return nil
+		}
+		err := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return err
+	}
+	for _, b := range program {
+		arg := errors.KindCode(b>>3) * 25
+		switch b % 8 {
+		case 0:
+			stack = append(stack, errors.E("app.Get", pop(), arg, errors.NoStack))
+		case 1:
+			stack = append(stack, fmt.Errorf("wrap: %w", pop()))
+		case 2:
+			stack = append(stack, stderrors.Join(pop(), pop()))
+		case 3:
+			stack = append(stack, typedNil)
+		case 4:
+			stack = append(stack, nil)
+		case 5:
+			stack = append(stack, errors.E("app.List", "failed", log.Level(arg), pop(), pop()))
+		case 6:
+			stack = append(stack, errors.With(pop(), errors.Fields{"b": b}))
+		case 7:
+			stack = append(stack, errors.E("", errors.ForceLevel(log.LevelWarn), errors.Sensitive, pop()))
+		}
+	}
+	return pop()
+}
+
+func FuzzChain(f *testing.F) {
+	f.Add([]byte{0, 1, 0})
+	f.Add([]byte{3, 0, 1, 4, 5, 2})
+	f.Add([]byte{0, 8, 17, 26, 35, 44, 53, 62, 7})
+	f.Add([]byte{4, 4, 2, 1, 6, 6})
+	f.Fuzz(func(t *testing.T, program []byte) {
+		err := buildChain(program)
+		if err == nil {
+			return
+		}
+
+		_ = errors.Kind(err)
+		_, _ = errors.KindExplicit(err)
+		_ = errors.Level(err)
+		_, _ = errors.LevelExplicit(err)
+		_ = errors.Msg(err)
+		_ = errors.ClientMsg(err)
+		_ = errors.Ops(err)
+		_ = errors.Frames(err)
+		_ = errors.FieldsOf(err)
+		if s := errors.Summary(err); s.Kind != errors.Kind(err) || s.Level != errors.Level(err) {
+			t.Errorf("Summary() = %d, %v, want %d, %v as Kind() and Level()", s.Kind, s.Level, errors.Kind(err), errors.Level(err))
+		}
+		_ = errors.Depth(err)
+		_ = errors.IsKind(err, errors.KindNotFound)
+		_ = errors.Root(err)
+		_ = err.Error()
+		_ = fmt.Sprintf("%+v", err)
+		if _, jerr := json.Marshal(err); jerr != nil {
+			t.Errorf("json.Marshal() error = %v", jerr)
+		}
+		if data, eerr := errors.Encode(err); eerr != nil {
+			t.Errorf("Encode() error = %v", eerr)
+		} else if _, derr := errors.Decode(data); derr != nil {
+			t.Errorf("Decode() error = %v", derr)
+		}
+	})
+}
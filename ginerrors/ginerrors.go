@@ -0,0 +1,62 @@
+// Package ginerrors provides a Gin middleware which handles
+// errors of go.nownabe.dev/errors attached to the context.
+package ginerrors // import "go.nownabe.dev/errors/ginerrors"
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.nownabe.dev/errors"
+)
+
+const op errors.Op = "ginerrors.ErrorHandler"
+
+// ErrorHandler returns a middleware which handles the last error
+// attached to the context by handlers. It writes the status from
+// the error's kind and a JSON body with the client message,
+// and logs the error with logger at its level.
+// *gin.Error is converted into an error of go.nownabe.dev/errors.
+func ErrorHandler(logger errors.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		ge := c.Errors.Last()
+		if ge == nil {
+			return
+		}
+		err := convert(ge, c.Writer.Status())
+		errors.LogContext(c.Request.Context(), logger, err)
+
+		if c.Writer.Written() {
+			return
+		}
+		c.AbortWithStatusJSON(int(errors.Kind(err)), gin.H{"message": message(err)})
+	}
+}
+
+func convert(ge *gin.Error, status int) error {
+	var e errors.Error
+	if stderrors.As(ge.Err, &e) {
+		return ge.Err
+	}
+
+	switch {
+	case ge.IsType(gin.ErrorTypeBind):
+		return errors.E(op, ge.Err, errors.KindBadRequest, ge.Error())
+	case ge.IsType(gin.ErrorTypePublic) && status >= http.StatusBadRequest:
+		return errors.E(op, ge.Err, errors.KindCode(status), ge.Error())
+	case ge.IsType(gin.ErrorTypePublic):
+		return errors.E(op, ge.Err, ge.Error())
+	}
+	return errors.E(op, ge.Err)
+}
+
+// message returns the client message, hiding it for server errors.
+func message(err error) string {
+	kind := errors.Kind(err)
+	if kind >= 500 {
+		return kind.String()
+	}
+	return errors.ClientMsg(err)
+}
@@ -1,8 +1,26 @@
 module go.nownabe.dev/errors
 
 require (
+	github.com/getsentry/sentry-go v0.29.1
+	github.com/prometheus/client_golang v1.20.5
 	go.nownabe.dev/log v1.0.2
-	golang.org/x/xerrors v0.0.0-20191011141410-1b5146add898
+	golang.org/x/text v0.16.0
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543
 )
 
-go 1.13
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	go.uber.org/atomic v1.4.0 // indirect
+	go.uber.org/multierr v1.1.0 // indirect
+	go.uber.org/zap v1.10.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
+
+go 1.23
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+var captureGoroutine atomic.Bool
+
+// CaptureGoroutineInfo sets whether E records the ID of the goroutine
+// constructing errors, and EC also the pprof labels of its context.
+// It is off by default because reading the ID costs a stack dump.
+func CaptureGoroutineInfo(enabled bool) {
+	captureGoroutine.Store(enabled)
+}
+
+type goroutineInfo struct {
+	id     int64
+	labels map[string]string
+}
+
+// String returns the ID followed by the sorted labels like "12 k=v".
+func (g *goroutineInfo) String() string {
+	keys := make([]string, 0, len(g.labels))
+	for k := range g.labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	s := strconv.FormatInt(g.id, 10)
+	for _, k := range keys {
+		s += " " + k + "=" + g.labels[k]
+	}
+	return s
+}
+
+// goroutineLabels is passed to newError by EC.
+type goroutineLabels map[string]string
+
+func contextLabels(ctx context.Context) goroutineLabels {
+	labels := goroutineLabels{}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		labels[key] = value
+		return true
+	})
+	return labels
+}
+
+// goroutineID parses the ID from the header "goroutine 123 [running]:".
+func goroutineID() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseInt(string(buf), 10, 64)
+	return id
+}
+
+// GoroutineOf returns the goroutine ID and the pprof labels recorded
+// by the outermost layer constructed while CaptureGoroutineInfo is on.
+func GoroutineOf(err error) (id int64, labels map[string]string, ok bool) {
+	walk(err, func(e *appError) bool {
+		if e.goroutine != nil {
+			id, labels, ok = e.goroutine.id, e.goroutine.labels, true
+		}
+		return !ok
+	})
+	return id, labels, ok
+}
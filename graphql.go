@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"strings"
+	"sync"
+)
+
+var graphQLCodes = struct {
+	sync.RWMutex
+	m map[KindCode]string
+}{m: map[KindCode]string{
+	KindBadRequest:          "BAD_USER_INPUT",
+	KindUnauthorized:        "UNAUTHENTICATED",
+	KindForbidden:           "FORBIDDEN",
+	KindNotFound:            "NOT_FOUND",
+	KindConflict:            "CONFLICT",
+	KindUnprocessableEntity: "BAD_USER_INPUT",
+	KindTooManyRequests:     "TOO_MANY_REQUESTS",
+	KindUnexpected:          "INTERNAL_SERVER_ERROR",
+}}
+
+// RegisterGraphQLCode registers the GraphQL error code of the kind.
+func RegisterGraphQLCode(kind KindCode, code string) {
+	graphQLCodes.Lock()
+	defer graphQLCodes.Unlock()
+	graphQLCodes.m[kind] = code
+}
+
+// GraphQLCode returns the GraphQL error code of the kind.
+//
+//	KindBadRequest          BAD_USER_INPUT
+//	KindUnauthorized        UNAUTHENTICATED
+//	KindForbidden           FORBIDDEN
+//	KindNotFound            NOT_FOUND
+//	KindConflict            CONFLICT
+//	KindUnprocessableEntity BAD_USER_INPUT
+//	KindTooManyRequests     TOO_MANY_REQUESTS
+//	KindUnexpected          INTERNAL_SERVER_ERROR
+//
+// Other kinds use their texts in upper snake case,
+// e.g. "SERVICE_UNAVAILABLE".
+func GraphQLCode(kind KindCode) string {
+	graphQLCodes.RLock()
+	code, ok := graphQLCodes.m[kind]
+	graphQLCodes.RUnlock()
+	if ok {
+		return code
+	}
+	return strings.ToUpper(strings.Join(strings.FieldsFunc(kind.String(), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}), "_"))
+}
+
+// GraphQLExtensions returns the extensions of a GraphQL error
+// with "code", "httpStatus", "ops" and "errors" for field errors.
+// "code" is the code set with Code if any, or GraphQLCode of the kind.
+// It does not include messages; use ClientMsg for the message
+// of the GraphQL error so that internal details are not exposed.
+func GraphQLExtensions(err error) map[string]interface{} {
+	kind := Kind(err)
+	code := GraphQLCode(kind)
+	if c := CodeOf(err); c != "" {
+		code = string(c)
+	}
+	ext := map[string]interface{}{
+		"code":       code,
+		"httpStatus": int(kind),
+		"ops":        Ops(err),
+	}
+	if fes, ok := hasFieldErrors(err); ok {
+		ext["errors"] = fes
+	}
+	return ext
+}
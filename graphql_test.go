@@ -0,0 +1,71 @@
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestGraphQLCode(t *testing.T) {
+	errors.RegisterGraphQLCode(errors.KindGone, "DELETED")
+	defer errors.RegisterGraphQLCode(errors.KindGone, "GONE")
+
+	tests := map[errors.KindCode]string{
+		errors.KindBadRequest:          "BAD_USER_INPUT",
+		errors.KindUnprocessableEntity: "BAD_USER_INPUT",
+		errors.KindUnauthorized:        "UNAUTHENTICATED",
+		errors.KindNotFound:            "NOT_FOUND",
+		errors.KindUnexpected:          "INTERNAL_SERVER_ERROR",
+		errors.KindServiceUnavailable:  "SERVICE_UNAVAILABLE",
+		errors.KindPreconditionFailed:  "PRECONDITION_FAILED",
+		errors.KindGone:                "DELETED",
+	}
+	for kind, want := range tests {
+		t.Run(kind.String(), func(t *testing.T) {
+			if got := errors.GraphQLCode(kind); got != want {
+				t.Errorf("GraphQLCode(%d) = %q, want %q", kind, got, want)
+			}
+		})
+	}
+}
+
+func TestGraphQLExtensions(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want map[string]interface{}
+	}{
+		"kind": {
+			err: errors.E("app.Handle", errors.E("app.Get", errors.KindNotFound, "no user u1")),
+			want: map[string]interface{}{
+				"code":       "NOT_FOUND",
+				"httpStatus": 404,
+				"ops":        []string{"app.Handle", "app.Get"},
+			},
+		},
+		"code": {
+			err: errors.E("app.Get", errors.KindNotFound, errors.Code("user_not_found")),
+			want: map[string]interface{}{
+				"code":       "user_not_found",
+				"httpStatus": 404,
+				"ops":        []string{"app.Get"},
+			},
+		},
+		"field errors": {
+			err: errors.E("app.Create", errors.KindBadRequest, errors.FieldErrors{"name": {"is required"}}),
+			want: map[string]interface{}{
+				"code":       "BAD_USER_INPUT",
+				"httpStatus": 400,
+				"ops":        []string{"app.Create"},
+				"errors":     errors.FieldErrors{"name": {"is required"}},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.GraphQLExtensions(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GraphQLExtensions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
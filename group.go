@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Group runs functions in goroutines and collects all of their errors,
+// unlike errgroup which keeps only the first one.
+// The zero value is ready to use and does not cancel anything on failure.
+// A Group must not be reused after Wait.
+type Group struct {
+	// Op is the op of the error returned by Wait.
+	Op Op
+
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	next   atomic.Int64
+	batch  Batch
+	cancel context.CancelCauseFunc
+}
+
+// NewGroup returns a Group whose error has op.
+func NewGroup(op Op) *Group {
+	return &Group{Op: op}
+}
+
+// GroupWithContext returns a Group and a context derived from ctx
+// which is canceled when the first function fails or Wait returns.
+func GroupWithContext(ctx context.Context, op Op) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{Op: op, cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of goroutines running at once to n.
+// Go blocks until a slot is free. A negative n means no limit.
+// It must not be called while goroutines are running.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go calls f in a new goroutine. A non-nil error returned by f
+// is collected with fields of its index, the order of the call to Go,
+// and the ID of the goroutine. Errors not constructed by E are
+// wrapped with the op of g, or of the caller of Go if it is empty.
+func (g *Group) Go(f func() error) {
+	index := int(g.next.Add(1) - 1)
+	op := g.Op
+	if op == "" {
+		if pc, _, _, ok := runtime.Caller(1); ok {
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				op = funcOp(fn.Name())
+			}
+		}
+	}
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		err := f()
+		if isNil(err) {
+			return
+		}
+		fields := Fields{"index": index, "goroutine": goroutineID()}
+		if _, ok := err.(*appError); ok {
+			err = With(err, fields)
+		} else {
+			err = newError(0, op, []interface{}{err, fields, NoStack})
+		}
+		g.batch.Add(index, err)
+		if g.cancel != nil {
+			g.cancel(err)
+		}
+	}()
+}
+
+// Wait waits for all the functions to return and returns an error
+// which wraps all of their errors like Batch.Err, or nil if none failed.
+// Its kind and level are the most severe ones among the errors.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+	g.batch.Op = g.Op
+	g.batch.Total = int(g.next.Load())
+	return g.batch.Err()
+}
@@ -0,0 +1,115 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+// members returns the errors collected by a Group.
+func members(t *testing.T, err error) []error {
+	t.Helper()
+	var j interface{ Unwrap() []error }
+	if !stderrors.As(err, &j) {
+		t.Fatalf("Wait() = %v, want the joined errors", err)
+	}
+	return j.Unwrap()
+}
+
+func TestGroupLimit(t *testing.T) {
+	const n, limit = 200, 4
+	g := errors.NewGroup("app.Import")
+	g.SetLimit(limit)
+
+	var running, peak, failed atomic.Int64
+	for i := range n {
+		g.Go(func() error {
+			cur := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if cur <= p || peak.CompareAndSwap(p, cur) {
+					break
+				}
+			}
+			switch i % 3 {
+			case 0:
+				failed.Add(1)
+				return errors.E("app.Parse", errors.KindBadRequest, "bad row")
+			case 1:
+				failed.Add(1)
+				return fmt.Errorf("row %d: invalid", i)
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	if p := peak.Load(); p > limit {
+		t.Errorf("%d goroutines ran at once, want at most %d", p, limit)
+	}
+	errs := members(t, err)
+	if got, want := len(errs), int(failed.Load()); got != want {
+		t.Fatalf("%d errors collected, want %d", got, want)
+	}
+	seen := map[interface{}]bool{}
+	for _, e := range errs {
+		index := errors.FieldsOf(e)["index"]
+		if seen[index] {
+			t.Errorf("index %v collected twice", index)
+		}
+		seen[index] = true
+	}
+	if got := errors.Kind(err); got != errors.KindUnexpected {
+		t.Errorf("Kind() = %d, want %d", got, errors.KindUnexpected)
+	}
+}
+
+func TestGroupConcurrentGo(t *testing.T) {
+	g := errors.NewGroup("app.Import")
+	g.SetLimit(8)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				g.Go(func() error { return errors.E("app.Parse", errors.KindBadRequest) })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(members(t, g.Wait())); got != 400 {
+		t.Errorf("%d errors collected, want 400", got)
+	}
+}
+
+func TestGroupForeignOp(t *testing.T) {
+	tests := map[string]struct {
+		g    *errors.Group
+		want string
+	}{
+		"group op":   {errors.NewGroup("app.Import"), "app.Import"},
+		"zero value": {&errors.Group{}, "errors_test.TestGroupForeignOp.func1"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt.g.Go(func() error { return stderrors.New("failed") })
+			err := tt.g.Wait()
+
+			errs := members(t, err)
+			if len(errs) != 1 {
+				t.Fatalf("%d errors collected, want 1", len(errs))
+			}
+			if got := errors.Ops(errs[0]); len(got) != 1 || got[0] != tt.want {
+				t.Errorf("Ops() = %v, want [%s]", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+module go.nownabe.dev/errors/grpcerrors
+
+require (
+	go.nownabe.dev/errors v0.0.0
+	google.golang.org/genproto v0.0.0-20191108220845-16a3f7862a1a
+	google.golang.org/grpc v1.25.1
+)
+
+require (
+	github.com/golang/protobuf v1.3.2 // indirect
+	go.nownabe.dev/log v1.0.2 // indirect
+	go.uber.org/atomic v1.4.0 // indirect
+	go.uber.org/multierr v1.1.0 // indirect
+	go.uber.org/zap v1.10.0 // indirect
+	golang.org/x/net v0.0.0-20190311183353-d8887717615a // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+)
+
+replace go.nownabe.dev/errors => ../
+
+go 1.23
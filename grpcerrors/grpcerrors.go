@@ -0,0 +1,108 @@
+// Package grpcerrors converts errors of go.nownabe.dev/errors
+// from and into gRPC statuses.
+package grpcerrors // import "go.nownabe.dev/errors/grpcerrors"
+
+import (
+	"context"
+	stderrors "errors"
+
+	"go.nownabe.dev/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCCode returns the gRPC code corresponding to the error's kind.
+// Plain errors are mapped to codes.Internal and unknown kinds
+// to codes.Unknown.
+func GRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	var e errors.Error
+	if !stderrors.As(err, &e) {
+		return codes.Internal
+	}
+	if c, ok := codeOf(errors.Kind(err)); ok {
+		return c
+	}
+	return codes.Unknown
+}
+
+// ToStatus converts the error into a gRPC status
+// which carries errors.Msg as its message and errors.DetailsOf
+// as LocalizedMessage details.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	st := status.New(GRPCCode(err), errors.Msg(err))
+	for _, d := range errors.DetailsOf(err) {
+		if s, derr := st.WithDetails(&errdetails.LocalizedMessage{Locale: "en-US", Message: d}); derr == nil {
+			st = s
+		}
+	}
+	return st
+}
+
+// ToStatusWithChain is like ToStatus but also carries the chain
+// encoded by errors.Encode as a DebugInfo detail so that FromStatus
+// can restore it. Use it only between trusted services.
+func ToStatusWithChain(err error) *status.Status {
+	st := ToStatus(err)
+	if err == nil {
+		return st
+	}
+	data, eerr := errors.Encode(err)
+	if eerr != nil {
+		return st
+	}
+	if s, derr := st.WithDetails(&errdetails.DebugInfo{Detail: string(data)}); derr == nil {
+		st = s
+	}
+	return st
+}
+
+// FromStatus constructs an error from a gRPC status received by a client.
+// If the status carries a chain attached by ToStatusWithChain,
+// the decoded chain is wrapped. Otherwise, the kind is taken from the code
+// by KindFromGRPC and the message from the status.
+func FromStatus(op errors.Op, st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.DebugInfo)
+		if !ok {
+			continue
+		}
+		if decoded, derr := errors.Decode([]byte(info.Detail)); derr == nil && decoded != nil {
+			return errors.ECaller(1, op, decoded)
+		}
+	}
+	args := []interface{}{KindFromGRPC(st.Code())}
+	if msg := st.Message(); msg != "" {
+		args = append(args, msg)
+	}
+	return errors.ECaller(1, op, args...)
+}
+
+// UnaryServerInterceptor converts errors returned by handlers
+// into gRPC statuses and logs them at their levels.
+func UnaryServerInterceptor(logger errors.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		errors.LogContext(ctx, logger, err)
+
+		var e errors.Error
+		if !stderrors.As(err, &e) {
+			return resp, err
+		}
+		return resp, ToStatus(err).Err()
+	}
+}
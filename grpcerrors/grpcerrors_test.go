@@ -0,0 +1,89 @@
+package grpcerrors_test
+
+import (
+	stderrors "errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/errors/grpcerrors"
+	"go.nownabe.dev/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFromStatus(t *testing.T) {
+	tests := map[string]struct {
+		st       *status.Status
+		wantKind errors.KindCode
+		wantMsg  string
+	}{
+		"mapped":   {status.New(codes.NotFound, "no user"), errors.KindNotFound, "no user"},
+		"unmapped": {status.New(codes.DataLoss, "corrupted"), errors.KindUnexpected, "corrupted"},
+		"no msg":   {status.New(codes.Unavailable, ""), errors.KindServiceUnavailable, "Service Unavailable"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := grpcerrors.FromStatus("client.Get", tt.st)
+			if got := errors.Kind(err); got != tt.wantKind {
+				t.Errorf("Kind() = %d, want %d", got, tt.wantKind)
+			}
+			if got := errors.Msg(err); got != tt.wantMsg {
+				t.Errorf("Msg() = %q, want %q", got, tt.wantMsg)
+			}
+			if got := errors.Ops(err); !reflect.DeepEqual(got, []string{"client.Get"}) {
+				t.Errorf("Ops() = %v, want [client.Get]", got)
+			}
+		})
+	}
+}
+
+func TestFromStatusChain(t *testing.T) {
+	err := errors.E("app.Handle", "handle",
+		errors.E("app.Get", errors.KindNotFound, log.LevelWarn, errors.Fields{"user_id": "u1"},
+			stderrors.New("no rows")))
+
+	got := grpcerrors.FromStatus("client.Get", grpcerrors.ToStatusWithChain(err))
+
+	if k := errors.Kind(got); k != errors.KindNotFound {
+		t.Errorf("Kind() = %d, want %d", k, errors.KindNotFound)
+	}
+	if l := errors.Level(got); l != log.LevelWarn {
+		t.Errorf("Level() = %v, want %v", l, log.LevelWarn)
+	}
+	if msg, want := errors.Msg(got), errors.Msg(err); msg != want {
+		t.Errorf("Msg() = %q, want %q", msg, want)
+	}
+	if ops, want := errors.Ops(got), []string{"client.Get", "app.Handle", "app.Get"}; !reflect.DeepEqual(ops, want) {
+		t.Errorf("Ops() = %v, want %v", ops, want)
+	}
+	if f := errors.FieldsOf(got); f["user_id"] != "u1" {
+		t.Errorf("FieldsOf() = %v, want user_id", f)
+	}
+	if errors.Stacktrace(got) == nil {
+		t.Error("Stacktrace() = nil, want the remote stack")
+	}
+}
+
+func TestFromStatusOK(t *testing.T) {
+	if err := grpcerrors.FromStatus("client.Get", status.New(codes.OK, "")); err != nil {
+		t.Errorf("FromStatus(OK) = %v, want nil", err)
+	}
+	if err := grpcerrors.FromStatus("client.Get", nil); err != nil {
+		t.Errorf("FromStatus(nil) = %v, want nil", err)
+	}
+}
+
+func TestFromStatusSensitive(t *testing.T) {
+	err := errors.E("app.Get", errors.KindNotFound, errors.Sensitive, "no user alice@example.com")
+
+	got := grpcerrors.FromStatus("client.Get", grpcerrors.ToStatusWithChain(err))
+
+	if msg := errors.Msg(got); msg != "Not Found" {
+		t.Errorf("Msg() = %q, want the kind text", msg)
+	}
+	if msg := errors.ClientMsg(got); strings.Contains(msg, "alice") {
+		t.Errorf("ClientMsg() = %q, want the message hidden", msg)
+	}
+}
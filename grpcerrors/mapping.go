@@ -0,0 +1,71 @@
+package grpcerrors
+
+import (
+	"sync"
+
+	"go.nownabe.dev/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// mappings is the single table between kinds and gRPC codes
+// used in both directions so that they never drift.
+var mappings = struct {
+	sync.RWMutex
+	codes map[errors.KindCode]codes.Code
+	kinds map[codes.Code]errors.KindCode
+}{
+	codes: map[errors.KindCode]codes.Code{},
+	kinds: map[codes.Code]errors.KindCode{},
+}
+
+func init() {
+	RegisterMapping(errors.KindBadRequest, codes.InvalidArgument)
+	RegisterMapping(errors.KindUnauthorized, codes.Unauthenticated)
+	RegisterMapping(errors.KindForbidden, codes.PermissionDenied)
+	RegisterMapping(errors.KindNotFound, codes.NotFound)
+	RegisterMapping(errors.KindConflict, codes.AlreadyExists)
+	RegisterMapping(errors.KindPreconditionFailed, codes.FailedPrecondition)
+	RegisterMapping(errors.KindTooManyRequests, codes.ResourceExhausted)
+	RegisterMapping(errors.KindClientClosedRequest, codes.Canceled)
+	RegisterMapping(errors.KindUnexpected, codes.Internal)
+	RegisterMapping(errors.KindNotImplemented, codes.Unimplemented)
+	RegisterMapping(errors.KindServiceUnavailable, codes.Unavailable)
+	RegisterMapping(errors.KindGatewayTimeout, codes.DeadlineExceeded)
+}
+
+// RegisterMapping maps kind to code and code back to kind.
+// Existing mappings of either of them are replaced so that
+// converting a registered kind to its code and back returns the kind.
+func RegisterMapping(kind errors.KindCode, code codes.Code) {
+	mappings.Lock()
+	defer mappings.Unlock()
+	if c, ok := mappings.codes[kind]; ok {
+		delete(mappings.kinds, c)
+	}
+	if k, ok := mappings.kinds[code]; ok {
+		delete(mappings.codes, k)
+	}
+	mappings.codes[kind] = code
+	mappings.kinds[code] = kind
+}
+
+func codeOf(kind errors.KindCode) (codes.Code, bool) {
+	mappings.RLock()
+	defer mappings.RUnlock()
+	c, ok := mappings.codes[kind]
+	return c, ok
+}
+
+// KindFromGRPC returns the kind corresponding to code.
+// codes.OK is mapped to 0 and unknown codes to errors.KindUnexpected.
+func KindFromGRPC(code codes.Code) errors.KindCode {
+	if code == codes.OK {
+		return 0
+	}
+	mappings.RLock()
+	defer mappings.RUnlock()
+	if k, ok := mappings.kinds[code]; ok {
+		return k
+	}
+	return errors.KindUnexpected
+}
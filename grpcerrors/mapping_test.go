@@ -0,0 +1,65 @@
+package grpcerrors
+
+import (
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"google.golang.org/grpc/codes"
+)
+
+func TestMappingRoundTrip(t *testing.T) {
+	mappings.RLock()
+	table := make(map[errors.KindCode]codes.Code, len(mappings.codes))
+	for k, c := range mappings.codes {
+		table[k] = c
+	}
+	mappings.RUnlock()
+
+	if len(table) == 0 {
+		t.Fatal("no mappings registered")
+	}
+	for kind, code := range table {
+		t.Run(kind.String(), func(t *testing.T) {
+			err := errors.E("app.Get", kind)
+			if got := GRPCCode(err); got != code {
+				t.Errorf("GRPCCode() = %s, want %s", got, code)
+			}
+			if got := ToStatus(err).Code(); got != code {
+				t.Errorf("ToStatus().Code() = %s, want %s", got, code)
+			}
+			if got := KindFromGRPC(code); got != kind {
+				t.Errorf("KindFromGRPC(%s) = %d, want %d", code, got, kind)
+			}
+		})
+	}
+}
+
+func TestRegisterMapping(t *testing.T) {
+	defer func() {
+		mappings.Lock()
+		delete(mappings.codes, errors.KindGone)
+		delete(mappings.kinds, codes.Aborted)
+		mappings.Unlock()
+		RegisterMapping(errors.KindConflict, codes.AlreadyExists)
+	}()
+
+	RegisterMapping(errors.KindGone, codes.Aborted)
+	if got := GRPCCode(errors.E("app.Get", errors.KindGone)); got != codes.Aborted {
+		t.Errorf("GRPCCode() = %s, want %s", got, codes.Aborted)
+	}
+	if got := KindFromGRPC(codes.Aborted); got != errors.KindGone {
+		t.Errorf("KindFromGRPC() = %d, want %d", got, errors.KindGone)
+	}
+
+	// Remapping a code replaces the mapping of its previous kind.
+	RegisterMapping(errors.KindConflict, codes.Aborted)
+	if got := KindFromGRPC(codes.Aborted); got != errors.KindConflict {
+		t.Errorf("KindFromGRPC() = %d, want %d", got, errors.KindConflict)
+	}
+	if got := GRPCCode(errors.E("app.Get", errors.KindGone)); got != codes.Unknown {
+		t.Errorf("GRPCCode() of the replaced kind = %s, want %s", got, codes.Unknown)
+	}
+	if got := KindFromGRPC(codes.AlreadyExists); got != errors.KindUnexpected {
+		t.Errorf("KindFromGRPC() of the replaced code = %d, want %d", got, errors.KindUnexpected)
+	}
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPHeader is an HTTP response header passed to E.
+// It is written by the HTTP writers but never rendered in bodies.
+type HTTPHeader struct {
+	Key   string
+	Value string
+
+	retryAfter time.Duration
+}
+
+// Header returns an HTTPHeader which can be passed to E repeatedly.
+func Header(key, value string) HTTPHeader {
+	return HTTPHeader{Key: http.CanonicalHeaderKey(key), Value: value}
+}
+
+// RetryAfterHeader returns a Retry-After header in seconds rounded up.
+// The duration is also returned by RetryAfter.
+func RetryAfterHeader(d time.Duration) HTTPHeader {
+	secs := int(math.Ceil(d.Seconds()))
+	if secs < 0 {
+		secs = 0
+	}
+	return HTTPHeader{Key: "Retry-After", Value: strconv.Itoa(secs), retryAfter: d}
+}
+
+// HeadersOf returns the headers passed to E in err's chain.
+// Where layers set the same key, the outermost layer wins.
+func HeadersOf(err error) http.Header {
+	h := http.Header{}
+	walk(err, func(e *appError) bool {
+		layer := http.Header{}
+		for _, hd := range e.headers {
+			if _, ok := h[hd.Key]; !ok {
+				layer.Add(hd.Key, hd.Value)
+			}
+		}
+		for k, v := range layer {
+			h[k] = v
+		}
+		return true
+	})
+	return h
+}
+
+func setHeaders(w http.ResponseWriter, err error) {
+	for k, v := range HeadersOf(err) {
+		w.Header()[k] = v
+	}
+}
@@ -0,0 +1,84 @@
+package errors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestHeadersOf(t *testing.T) {
+	inner := errors.E("app.Get", errors.KindUnauthorized,
+		errors.Header("www-authenticate", `Bearer realm="inner"`), errors.Header("Link", "</a>"), errors.Header("Link", "</b>"))
+	err := errors.E("app.Handle", inner, errors.Header("WWW-Authenticate", `Bearer realm="outer"`))
+
+	want := http.Header{
+		"Www-Authenticate": {`Bearer realm="outer"`},
+		"Link":             {"</a>", "</b>"},
+	}
+	if got := errors.HeadersOf(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("HeadersOf() = %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	tests := map[string]struct {
+		d    time.Duration
+		want string
+	}{
+		"seconds":  {3 * time.Second, "3"},
+		"rounded":  {1500 * time.Millisecond, "2"},
+		"negative": {-time.Second, "0"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := errors.RetryAfterHeader(tt.d)
+			if h.Key != "Retry-After" || h.Value != tt.want {
+				t.Errorf("RetryAfterHeader() = %s: %s, want Retry-After: %s", h.Key, h.Value, tt.want)
+			}
+		})
+	}
+
+	err := errors.E("app.Get", errors.KindTooManyRequests, errors.RetryAfterHeader(3*time.Second))
+	if d, ok := errors.RetryAfter(err); !ok || d != 3*time.Second {
+		t.Errorf("RetryAfter() = %v, %v, want 3s", d, ok)
+	}
+}
+
+func TestWriteHeaders(t *testing.T) {
+	err := errors.E("app.Get", errors.KindServiceUnavailable, errors.RetryAfterHeader(time.Minute))
+
+	writers := map[string]func(w http.ResponseWriter, r *http.Request){
+		"WriteHTTP":    func(w http.ResponseWriter, r *http.Request) { errors.WriteHTTP(w, r, err) },
+		"WriteProblem": func(w http.ResponseWriter, r *http.Request) { errors.WriteProblem(w, r, err) },
+	}
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			write(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			if got := rec.Header().Get("Retry-After"); got != "60" {
+				t.Errorf("Retry-After = %q, want 60", got)
+			}
+			if body := rec.Body.String(); strings.Contains(body, "Retry-After") {
+				t.Errorf("body %s contains the header", body)
+			}
+		})
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	err := errors.E("app.Get", errors.Header("Link", "</a>"), errors.Header("Link", "</b>"), errors.Header("Link", "</c>"))
+	d := errors.With(err, errors.Header("Link", "</d>"))
+	_ = errors.With(err, errors.Header("Link", "</e>"))
+
+	if got, want := errors.HeadersOf(err)["Link"], []string{"</a>", "</b>", "</c>"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Link = %v, want the original %v", got, want)
+	}
+	if got, want := errors.HeadersOf(d)["Link"], []string{"</a>", "</b>", "</c>", "</d>"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Link = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+package errors
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+type hook struct {
+	fn        func(e Error)
+	summaryFn func(s ErrorSummary)
+}
+
+var hooks struct {
+	sync.RWMutex
+	list []*hook
+}
+
+// OnError registers fn to be called synchronously whenever an error
+// is constructed. Panics in fn are recovered. Errors constructed by
+// the hooks themselves are not passed to the hooks again. It returns
+// a function which removes the hook.
+//
+// For example, errors can be counted with Prometheus:
+//
+//	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+//		Name: "app_errors_total",
+//	}, []string{"kind", "category", "op"})
+//	errors.OnError(func(e errors.Error) {
+//		counter.WithLabelValues(strconv.Itoa(int(errors.Kind(e))), errors.Category(e), string(e.Op())).Inc()
+//	})
+func OnError(fn func(e Error)) (remove func()) {
+	return addHook(&hook{fn: fn})
+}
+
+// OnSummary is like OnError but fn receives the Summary of the error,
+// which is computed once for all the hooks registered by OnSummary.
+func OnSummary(fn func(s ErrorSummary)) (remove func()) {
+	return addHook(&hook{summaryFn: fn})
+}
+
+func addHook(h *hook) (remove func()) {
+	hooks.Lock()
+	hooks.list = append(hooks.list, h)
+	hooks.Unlock()
+
+	return func() {
+		hooks.Lock()
+		defer hooks.Unlock()
+		for i, x := range hooks.list {
+			if x == h {
+				hooks.list = append(hooks.list[:i:i], hooks.list[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// ResetHooks removes all the hooks registered by OnError and OnSummary.
+func ResetHooks() {
+	hooks.Lock()
+	defer hooks.Unlock()
+	hooks.list = nil
+}
+
+func notify(e *appError) {
+	record(e)
+
+	hooks.RLock()
+	list := hooks.list
+	hooks.RUnlock()
+	if len(list) == 0 {
+		return
+	}
+	id := goid()
+	if _, ok := dispatching.LoadOrStore(id, struct{}{}); ok {
+		return
+	}
+	defer dispatching.Delete(id)
+
+	var s *ErrorSummary
+	for _, h := range list {
+		if h.fn != nil {
+			call(func() { h.fn(e) })
+			continue
+		}
+		if s == nil {
+			sum := Summary(e)
+			s = &sum
+		}
+		call(func() { h.summaryFn(*s) })
+	}
+}
+
+// dispatching holds the IDs of the goroutines running hooks
+// so that errors constructed by the hooks are not passed to them again.
+var dispatching sync.Map
+
+// goid returns the ID of the current goroutine
+// from the header of its stack, "goroutine 1 [running]:".
+func goid() uint64 {
+	var buf [32]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+func call(fn func()) {
+	defer func() { _ = recover() }()
+	fn()
+}
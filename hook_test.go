@@ -0,0 +1,128 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.nownabe.dev/errors"
+)
+
+func TestOnErrorReentrant(t *testing.T) {
+	var mu sync.Mutex
+	var ops []errors.Op
+	remove := errors.OnError(func(e errors.Error) {
+		mu.Lock()
+		ops = append(ops, e.Op())
+		mu.Unlock()
+		_ = errors.E("hook.Report", e, "report")
+	})
+	defer remove()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = errors.E("app.Get", errors.KindNotFound)
+		}()
+	}
+	wg.Wait()
+
+	if len(ops) != 8 {
+		t.Fatalf("hook called %d times, want 8", len(ops))
+	}
+	for _, op := range ops {
+		if op != "app.Get" {
+			t.Errorf("hook called with op %s constructed by the hook", op)
+		}
+	}
+}
+
+func deep(n int, fn func()) {
+	if n == 0 {
+		fn()
+		return
+	}
+	deep(n-1, fn)
+}
+
+// TestOnErrorReentrantDeep covers hooks constructing errors
+// far below the dispatch in the stack.
+func TestOnErrorReentrantDeep(t *testing.T) {
+	var calls int
+	remove := errors.OnError(func(e errors.Error) {
+		calls++
+		deep(200, func() { _ = errors.E("hook.Report", e, "report") })
+	})
+	defer remove()
+
+	deep(200, func() { _ = errors.E("app.Get", errors.KindNotFound) })
+	if calls != 1 {
+		t.Errorf("hook called %d times, want 1", calls)
+	}
+}
+
+func TestOnErrorComplete(t *testing.T) {
+	var body string
+	remove := errors.OnError(func(e errors.Error) {
+		if e.Op() == "app.Import" {
+			b, _ := json.Marshal(e)
+			body = string(b)
+		}
+	})
+	defer remove()
+
+	b := errors.NewBatch("app.Import", 3)
+	b.Add(1, errors.E("app.Parse", errors.KindBadRequest, "bad row"))
+	_ = b.Err()
+
+	if !strings.Contains(body, `"items":[{"index":1`) {
+		t.Errorf("hook observed %s, want the batch items", body)
+	}
+}
+
+func TestOnSummary(t *testing.T) {
+	var got []errors.ErrorSummary
+	remove1 := errors.OnSummary(func(s errors.ErrorSummary) { got = append(got, s) })
+	remove2 := errors.OnSummary(func(s errors.ErrorSummary) { got = append(got, s) })
+	defer remove1()
+
+	err := errors.E("app.Get", errors.NoStack, errors.KindNotFound, "no user")
+	if len(got) != 2 {
+		t.Fatalf("hooks called %d times, want 2", len(got))
+	}
+	for _, s := range got {
+		if s != errors.Summary(err) {
+			t.Errorf("hook called with %+v, want %+v", s, errors.Summary(err))
+		}
+	}
+
+	remove2()
+	got = nil
+	_ = errors.E("app.Get", errors.NoStack, errors.KindNotFound)
+	if len(got) != 1 {
+		t.Errorf("hooks called %d times after removing one, want 1", len(got))
+	}
+}
+
+func ExampleOnError() {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "app_errors_total",
+	}, []string{"kind", "category", "op"})
+	remove := errors.OnError(func(e errors.Error) {
+		counter.WithLabelValues(strconv.Itoa(int(errors.Kind(e))), errors.Category(e), string(e.Op())).Inc()
+	})
+	defer remove()
+
+	_ = errors.E("app.Get", errors.KindNotFound)
+	_ = errors.E("app.Get", errors.KindNotFound)
+
+	fmt.Println(testutil.ToFloat64(counter.WithLabelValues("404", errors.Uncategorized, "app.Get")))
+	// Output: 2
+}
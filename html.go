@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+// HTMLPage is the data passed to templates by WriteHTML.
+type HTMLPage struct {
+	Status    int
+	Title     string
+	Message   string
+	RequestID string
+}
+
+var defaultHTMLTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Status}} {{.Title}}</title></head>
+<body>
+<h1>{{.Status}} {{.Title}}</h1>
+<p>{{.Message}}</p>
+{{if .RequestID}}<p>Request ID: {{.RequestID}}</p>{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the error as an HTML page executing tmpl with
+// an HTMLPage, or a minimal built-in page if tmpl is nil.
+// Server error kinds show the kind text and TicketCode only.
+// RequestID is the field "request_id" if present. Responses to
+// canceled requests are aborted as in WriteHTTP.
+func WriteHTML(w http.ResponseWriter, r *http.Request, err error, tmpl *template.Template) {
+	if tmpl == nil {
+		tmpl = defaultHTMLTemplate
+	}
+	kind := responseKind(r, err)
+	page := HTMLPage{Status: int(kind), Title: kind.String(), Message: serverMsg(err, kind)}
+	if kind < 500 {
+		page.Message = ClientMsg(err)
+	}
+	if id, ok := FieldsOf(err)["request_id"]; ok {
+		page.RequestID = fmt.Sprint(id)
+	}
+
+	var buf bytes.Buffer
+	if terr := tmpl.Execute(&buf, page); terr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	setHeaders(w, err)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(page.Status)
+	_, _ = buf.WriteTo(w)
+}
@@ -0,0 +1,71 @@
+package errors_test
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestWriteHTML(t *testing.T) {
+	tmpl := template.Must(template.New("page").Parse(`<h1>{{.Status}} {{.Title}}</h1><p>{{.Message}}</p><i>{{.RequestID}}</i>`))
+	err := errors.E("app.Get", errors.KindNotFound, "no <b>user</b>", errors.Fields{"request_id": "req-1"})
+
+	tests := map[string]struct {
+		tmpl *template.Template
+		want []string
+	}{
+		"custom":   {tmpl, []string{"<h1>404 Not Found</h1>", "<p>no &lt;b&gt;user&lt;/b&gt;", "<i>req-1</i>"}},
+		"fallback": {nil, []string{"<!DOCTYPE html>", "<title>404 Not Found</title>", "no &lt;b&gt;user&lt;/b&gt;", "Request ID: req-1"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			errors.WriteHTML(rec, httptest.NewRequest(http.MethodGet, "/", nil), err, tt.tmpl)
+
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+			}
+			body := rec.Body.String()
+			for _, w := range tt.want {
+				if !strings.Contains(body, w) {
+					t.Errorf("body %s does not contain %s", body, w)
+				}
+			}
+			if strings.Contains(body, "<b>") {
+				t.Errorf("body %s contains the unescaped message", body)
+			}
+		})
+	}
+}
+
+func TestWriteHTMLServerError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	errors.WriteHTML(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.E("app.Get", "database password is wrong"), nil)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "password") || !strings.Contains(body, "<p>Internal Server Error") {
+		t.Errorf("body %s, want the kind text only", body)
+	}
+}
+
+func TestWriteHTMLTemplateError(t *testing.T) {
+	tmpl := template.Must(template.New("page").Parse(`{{.Missing}}`))
+	rec := httptest.NewRecorder()
+	errors.WriteHTML(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.E("app.Get", errors.KindNotFound), tmpl)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
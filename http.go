@@ -0,0 +1,198 @@
+package errors
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// publicMsg returns the message safe to show to clients.
+// Server error kinds never expose their messages.
+func publicMsg(err error) string {
+	kind := Kind(err)
+	if kind >= 500 {
+		return kind.String()
+	}
+	return Msg(err)
+}
+
+// WriteHTTP writes the error to w with the status from its kind.
+// The body is JSON or plain text according to the request's
+// Accept header. Headers passed to E are also written.
+// Server error kinds write the kind text and TicketCode.
+// It returns false without writing if err is nil.
+// For KindClientClosedRequest, it aborts the response by panicking
+// with http.ErrAbortHandler if the client is gone, and writes
+// KindUnexpected otherwise.
+func WriteHTTP(w http.ResponseWriter, r *http.Request, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	kind := responseKind(r, err)
+	msg := serverMsg(err, kind)
+	if kind < 500 {
+		msg = publicMsg(err)
+	}
+	setHeaders(w, err)
+	writeHTTP(w, r, int(kind), msg)
+	return true
+}
+
+// serverMsg returns the message written for err with the status
+// of the server error kind. It is the kind text followed by
+// TicketCode so that users can report it to support.
+func serverMsg(err error, kind KindCode) string {
+	return kind.String() + " (" + TicketCode(err) + ")"
+}
+
+// responseKind returns the kind whose status is written in response to r.
+// Responses to canceled requests are aborted by panicking with
+// http.ErrAbortHandler when r's context is done since the client is gone.
+// Otherwise KindClientClosedRequest is not a status for clients,
+// so it is written as KindUnexpected.
+func responseKind(r *http.Request, err error) KindCode {
+	kind := Kind(err)
+	if kind != KindClientClosedRequest {
+		return kind
+	}
+	if r != nil && r.Context().Err() != nil {
+		panic(http.ErrAbortHandler)
+	}
+	return KindUnexpected
+}
+
+func writeHTTP(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	if acceptsText(r) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(msg + "\n"))
+		return
+	}
+	writeJSON(w, status, msg)
+}
+
+func writeJSON(w http.ResponseWriter, status int, msg string) {
+	body, _ := json.Marshal(struct {
+		Message string `json:"message"`
+	}{msg})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// HandlerFunc is an HTTP handler which returns an error.
+type HandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// OpFromRequest returns the op of r from its route pattern such as
+// "GET /users/{id}" set by http.ServeMux. Routes without a method
+// are prefixed with the request's method. It falls back to
+// the method and the path when r has no pattern.
+func OpFromRequest(r *http.Request) Op {
+	if r.Pattern == "" {
+		return Op(r.Method + " " + r.URL.Path)
+	}
+	if _, _, ok := strings.Cut(r.Pattern, " "); ok {
+		return Op(r.Pattern)
+	}
+	return Op(r.Method + " " + r.Pattern)
+}
+
+// Handler adapts h to http.Handler. When h returns an error,
+// it is wrapped with OpFromRequest if the request has a route pattern
+// and the outermost op is not set. Paths are not used as ops
+// since they are unbounded. Then it logs the error with logger
+// and writes the status from its kind and a JSON body with
+// the client message. Responses to canceled requests are aborted
+// as in WriteHTTP. Panics in h are recovered into errors with
+// KindUnexpected except http.ErrAbortHandler.
+// When h returns nil, nothing is written.
+func Handler(h HandlerFunc, logger Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := serve(h, w, r)
+		if err == nil {
+			return
+		}
+		if r.Pattern != "" && !hasOuterOp(err) {
+			err = E(OpFromRequest(r), err)
+		}
+		LogContext(r.Context(), logger, err)
+
+		kind := responseKind(r, err)
+		msg := serverMsg(err, kind)
+		if kind < 500 {
+			msg = ClientMsg(err)
+		}
+		setHeaders(w, err)
+		writeJSON(w, int(kind), msg)
+	})
+}
+
+func serve(h HandlerFunc, w http.ResponseWriter, r *http.Request) (err error) {
+	defer Recover("", &err)
+	return h(w, r)
+}
+
+// hasOuterOp reports whether the outermost layer constructed by E
+// has an op which is not derived from the caller.
+func hasOuterOp(err error) bool {
+	has := false
+	walk(err, func(e *appError) bool {
+		has = e.op != "" && !e.derivedOp
+		return false
+	})
+	return has
+}
+
+// acceptsText reports whether the request prefers text/plain over JSON.
+// The q-value of each type is taken from its most specific match
+// in the Accept header. Ties go to the type matched first,
+// and JSON is the default.
+func acceptsText(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	accept := r.Header.Get("Accept")
+	textQ, textAt := acceptQuality(accept, "text", "plain")
+	jsonQ, jsonAt := acceptQuality(accept, "application", "json")
+	if textQ <= 0 {
+		return false
+	}
+	return textQ > jsonQ || textQ == jsonQ && textAt < jsonAt
+}
+
+// acceptQuality returns the q-value of the media type typ/sub
+// in accept and the index of the matching entry,
+// or -1 if accept does not match it.
+func acceptQuality(accept, typ, sub string) (q float64, at int) {
+	q, at = -1, -1
+	specificity := -1
+	for i, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		s := -1
+		switch mt {
+		case typ + "/" + sub:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity, q, at = s, 1, i
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+	}
+	return q, at
+}
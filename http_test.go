@@ -0,0 +1,178 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Log(log.Level, string, ...interface{}) {}
+
+// opsLogger records the ops logged.
+type opsLogger struct{ ops []string }
+
+func (l *opsLogger) Log(_ log.Level, _ string, keysAndValues ...interface{}) {
+	l.ops = keysAndValues[1].([]string)
+}
+
+func TestWriteHTTPAccept(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"application/json", "application/json"},
+		{"text/plain", "text/plain; charset=utf-8"},
+		{"text/plain, application/json", "text/plain; charset=utf-8"},
+		{"application/json, text/plain", "application/json"},
+		{"text/plain;q=0.1, application/json", "application/json"},
+		{"application/json;q=0.5, text/plain;q=0.9", "text/plain; charset=utf-8"},
+		{"text/*, application/json;q=0.2", "text/plain; charset=utf-8"},
+		{"text/plain;q=0.5, */*", "application/json"},
+		{"text/plain;q=0, */*;q=0.1", "application/json"},
+		{"image/png", "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			errors.WriteHTTP(w, r, errors.E("app.Get", errors.KindNotFound, "no user"))
+
+			if got := w.Header().Get("Content-Type"); got != tt.want {
+				t.Errorf("Content-Type = %q, want %q", got, tt.want)
+			}
+			if w.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+			}
+			if !strings.Contains(w.Body.String(), "no user") {
+				t.Errorf("body = %q, want the message", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriteCanceled(t *testing.T) {
+	err := errors.E("app.Handle", errors.E("app.List", errors.E("app.Query",
+		fmt.Errorf("query: %w", context.Canceled))))
+	writers := map[string]http.HandlerFunc{
+		"WriteHTTP":          func(w http.ResponseWriter, r *http.Request) { errors.WriteHTTP(w, r, err) },
+		"WriteHTTPLocalized": func(w http.ResponseWriter, r *http.Request) { errors.WriteHTTPLocalized(w, r, err) },
+		"WriteProblem":       func(w http.ResponseWriter, r *http.Request) { errors.WriteProblem(w, r, err) },
+		"WriteJSONAPI":       func(w http.ResponseWriter, r *http.Request) { errors.WriteJSONAPI(w, r, err) },
+		"WriteHTML":          func(w http.ResponseWriter, r *http.Request) { errors.WriteHTML(w, r, err, nil) },
+		"Handler": errors.Handler(func(http.ResponseWriter, *http.Request) error {
+			return err
+		}, nopLogger{}).ServeHTTP,
+	}
+	for name, write := range writers {
+		t.Run(name+"/gone", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			defer func() {
+				if p := recover(); p != http.ErrAbortHandler {
+					t.Errorf("recovered %v, want http.ErrAbortHandler", p)
+				}
+				if rec.Body.Len() != 0 {
+					t.Errorf("body = %q, want empty", rec.Body.String())
+				}
+			}()
+			write(rec, r)
+		})
+		t.Run(name+"/connected", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			write(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+			if strings.Contains(rec.Body.String(), "canceled") {
+				t.Errorf("body = %q, want no internal message", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerAbort(t *testing.T) {
+	h := errors.Handler(func(http.ResponseWriter, *http.Request) error {
+		panic(http.ErrAbortHandler)
+	}, nopLogger{})
+	rec := httptest.NewRecorder()
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", p)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("body = %q, want empty", rec.Body.String())
+		}
+	}()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestHandlerOp(t *testing.T) {
+	tests := map[string]struct {
+		pattern string
+		err     error
+		want    []string
+	}{
+		"pattern":           {"GET /users/{id}", errors.E("", errors.KindNotFound), []string{"GET /users/{id}", "errors_test.TestHandlerOp"}},
+		"pattern no method": {"/users/{id}", errors.E("", errors.KindNotFound), []string{"GET /users/{id}", "errors_test.TestHandlerOp"}},
+		"outer op":          {"GET /users/{id}", errors.E("app.Get", errors.KindNotFound), []string{"app.Get"}},
+		"no pattern":        {"", errors.E("", errors.KindNotFound), []string{"errors_test.TestHandlerOp"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			logger := &opsLogger{}
+			var h http.Handler = errors.Handler(func(http.ResponseWriter, *http.Request) error {
+				return tt.err
+			}, logger)
+			if tt.pattern != "" {
+				mux := http.NewServeMux()
+				mux.Handle(tt.pattern, h)
+				h = mux
+			}
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/u1", nil))
+
+			if !reflect.DeepEqual(logger.ops, tt.want) {
+				t.Errorf("ops = %v, want %v", logger.ops, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteServerErrorTicket(t *testing.T) {
+	err := errors.E("app.Get", errors.KindServiceUnavailable, "database password is wrong")
+	writers := map[string]http.HandlerFunc{
+		"WriteHTTP":          func(w http.ResponseWriter, r *http.Request) { errors.WriteHTTP(w, r, err) },
+		"WriteHTTPLocalized": func(w http.ResponseWriter, r *http.Request) { errors.WriteHTTPLocalized(w, r, err) },
+		"WriteHTML":          func(w http.ResponseWriter, r *http.Request) { errors.WriteHTML(w, r, err, nil) },
+		"Handler": errors.Handler(func(http.ResponseWriter, *http.Request) error {
+			return err
+		}, nopLogger{}).ServeHTTP,
+	}
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			write(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			body := rec.Body.String()
+			if !strings.Contains(body, errors.TicketCode(err)) {
+				t.Errorf("body = %q, want the ticket code %s", body, errors.TicketCode(err))
+			}
+			if strings.Contains(body, "password") {
+				t.Errorf("body = %q, want no internal message", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// LocalizedMessage is a message accepted by E which is
+// resolved per language. See MsgKey.
+type LocalizedMessage struct {
+	key  string
+	args []interface{}
+}
+
+// MsgKey returns a message for E resolved from the translations
+// registered for key. The translations are formatted with args
+// by fmt.Sprintf, so that they can reorder args with
+// explicit argument indexes like "%[2]s".
+func MsgKey(key string, args ...interface{}) LocalizedMessage {
+	return LocalizedMessage{key: key, args: args}
+}
+
+var messages = struct {
+	sync.RWMutex
+	m   map[string]map[language.Tag]string
+	def language.Tag
+}{m: map[string]map[language.Tag]string{}, def: language.English}
+
+// RegisterMessage registers the translations of the message for key.
+func RegisterMessage(key string, translations map[language.Tag]string) {
+	messages.Lock()
+	defer messages.Unlock()
+	t := make(map[language.Tag]string, len(translations))
+	for tag, s := range translations {
+		t[tag] = s
+	}
+	messages.m[key] = t
+}
+
+// SetDefaultLanguage sets the language used when no translation
+// is registered for the requested language. It defaults to English.
+func SetDefaultLanguage(tag language.Tag) {
+	messages.Lock()
+	defer messages.Unlock()
+	messages.def = tag
+}
+
+func (m LocalizedMessage) resolve(tag language.Tag) string {
+	messages.RLock()
+	defer messages.RUnlock()
+
+	t := messages.m[m.key]
+	for ; ; tag = tag.Parent() {
+		if s, ok := t[tag]; ok {
+			return fmt.Sprintf(s, m.args...)
+		}
+		if tag == language.Und {
+			break
+		}
+	}
+	if s, ok := t[messages.def]; ok {
+		return fmt.Sprintf(s, m.args...)
+	}
+	return m.key
+}
+
+func defaultLanguage() language.Tag {
+	messages.RLock()
+	defer messages.RUnlock()
+	return messages.def
+}
+
+// LocalizedMsg returns the outermost message given by MsgKey
+// resolved for tag, falling back to the default language and then
+// to the raw key. Without MsgKey in the chain or if the chain is
+// Sensitive, it returns ClientMsg. Like ClientMsg, the result
+// is redacted and then limited as set by SetMsgLimits.
+func LocalizedMsg(err error, tag language.Tag) string {
+	var lm *LocalizedMessage
+	walk(err, func(e *appError) bool {
+		lm = e.msgKey
+		return lm == nil
+	})
+	if lm == nil || isSensitive(err) {
+		return ClientMsg(err)
+	}
+	return limitMsg(redact(lm.resolve(tag)))
+}
+
+// WriteHTTPLocalized is like WriteHTTP but localizes the message
+// for the language in the request's Accept-Language header.
+func WriteHTTPLocalized(w http.ResponseWriter, r *http.Request, err error) bool {
+	if err == nil {
+		return false
+	}
+	kind := responseKind(r, err)
+	msg := serverMsg(err, kind)
+	if kind < 500 {
+		msg = LocalizedMsg(err, requestLanguage(r))
+	}
+	setHeaders(w, err)
+	writeHTTP(w, r, int(kind), msg)
+	return true
+}
+
+func requestLanguage(r *http.Request) language.Tag {
+	if r == nil {
+		return defaultLanguage()
+	}
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return defaultLanguage()
+	}
+	return tags[0]
+}
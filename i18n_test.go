@@ -0,0 +1,51 @@
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"golang.org/x/text/language"
+)
+
+func TestLocalizedMsg(t *testing.T) {
+	errors.RegisterMessage("user.not_found", map[language.Tag]string{
+		language.English:  "user %s is not found",
+		language.Japanese: "ユーザー %s が見つかりません",
+	})
+
+	err := errors.E("app.Get", errors.KindNotFound, errors.MsgKey("user.not_found", "alice"))
+	tests := map[string]struct {
+		tag  language.Tag
+		want string
+	}{
+		"registered": {language.Japanese, "ユーザー alice が見つかりません"},
+		"fallback":   {language.French, "user alice is not found"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.LocalizedMsg(err, tt.tag); got != tt.want {
+				t.Errorf("LocalizedMsg() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizedMsgSensitive(t *testing.T) {
+	err := errors.E("app.Get", errors.KindNotFound, errors.Sensitive,
+		errors.MsgKey("user.not_found", "alice@example.com"))
+
+	if got := errors.LocalizedMsg(err, language.English); strings.Contains(got, "alice") {
+		t.Errorf("LocalizedMsg() = %q, want the message hidden", got)
+	}
+}
+
+func TestLocalizedMsgLimit(t *testing.T) {
+	errors.SetMsgLimits(8, true)
+	defer errors.SetMsgLimits(1<<10, true)
+
+	err := errors.E("app.Get", errors.KindNotFound, errors.MsgKey("unregistered\nkey with a long name"))
+	if got, want := errors.LocalizedMsg(err, language.English), "unregist… (25 bytes omitted)"; got != want {
+		t.Errorf("LocalizedMsg() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,11 @@
+package errors
+
+import "iter"
+
+// Nodes returns an iterator over the errors constructed by E
+// in err's chain from outermost to innermost. See Walk.
+func Nodes(err error) iter.Seq[Error] {
+	return func(yield func(Error) bool) {
+		Walk(err, yield)
+	}
+}
@@ -0,0 +1,20 @@
+package errors
+
+import "strings"
+
+// joinError wraps multiple errors passed to E.
+type joinError struct {
+	errs []error
+}
+
+func (err *joinError) Error() string {
+	msgs := make([]string, len(err.errs))
+	for i, e := range err.errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (err *joinError) Unwrap() []error {
+	return err.errs
+}
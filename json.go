@@ -0,0 +1,129 @@
+package errors
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.nownabe.dev/log"
+)
+
+type jsonError struct {
+	Ops         []string      `json:"ops"`
+	Kind        KindCode      `json:"kind"`
+	KindText    string        `json:"kind_text"`
+	Code        Code          `json:"code,omitempty"`
+	Category    string        `json:"category"`
+	Level       log.Level     `json:"level"`
+	Msg         string        `json:"msg"`
+	CreatedAt   *time.Time    `json:"created_at,omitempty"`
+	User        string        `json:"user,omitempty"`
+	Stacktrace  [][3]string   `json:"stacktrace"`
+	Fields      Fields        `json:"fields,omitempty"`
+	FieldErrors FieldErrors   `json:"errors,omitempty"`
+	Details     []string      `json:"details,omitempty"`
+	Items       []batchEntry  `json:"items,omitempty"`
+	Resources   []ResourceRef `json:"resources,omitempty"`
+	Related     []*jsonCause  `json:"related,omitempty"`
+	Service     string        `json:"service,omitempty"`
+	Version     string        `json:"version,omitempty"`
+	Host        string        `json:"host,omitempty"`
+	Cause       *jsonCause    `json:"cause,omitempty"`
+	Causes      []*jsonCause  `json:"causes,omitempty"`
+}
+
+type jsonCause struct {
+	Op        Op           `json:"op,omitempty"`
+	Kind      KindCode     `json:"kind,omitempty"`
+	Level     log.Level    `json:"level,omitempty"`
+	Msg       string       `json:"msg,omitempty"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+	Fields    Fields       `json:"fields,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Cause     *jsonCause   `json:"cause,omitempty"`
+	Causes    []*jsonCause `json:"causes,omitempty"`
+}
+
+// newJSONCause encodes the node of Tree and its children.
+func newJSONCause(node *Node) *jsonCause {
+	if node == nil {
+		return nil
+	}
+	c := &jsonCause{}
+	if e, ok := node.err.(*appError); ok {
+		c.Op, c.Kind, c.Level = e.op, e.kind, e.level
+		if e.msg != "" && !isSensitive(e) {
+			c.Msg = redact(e.msg)
+		}
+		c.CreatedAt, c.Fields = e.createdAt(), e.fields
+	} else {
+		c.Error = redact(node.Msg)
+	}
+	c.Cause, c.Causes = jsonCauses(node.Children)
+	return c
+}
+
+// jsonCauses encodes a single child as a cause and multiple children as causes.
+func jsonCauses(nodes []*Node) (*jsonCause, []*jsonCause) {
+	if len(nodes) == 1 {
+		return newJSONCause(nodes[0]), nil
+	}
+	var causes []*jsonCause
+	for _, n := range nodes {
+		causes = append(causes, newJSONCause(n))
+	}
+	return nil, causes
+}
+
+// MarshalJSON encodes the error's chain as JSON.
+// Wrapped errors are nested under "cause", or "causes" for
+// multiple errors, as in Tree, and plain errors are encoded
+// as {"error": "..."}.
+func MarshalJSON(err error) ([]byte, error) {
+	e, ok := err.(*appError)
+	if !ok {
+		return json.Marshal(newJSONCause(Tree(err)))
+	}
+	return e.MarshalJSON()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (err *appError) MarshalJSON() ([]byte, error) {
+	fields := FieldsOf(err)
+	if len(fields) == 0 {
+		fields = nil
+	}
+	fes, _ := hasFieldErrors(err)
+	svc, version, host := buildOf(err)
+	cause, causes := jsonCauses(Tree(err).Children)
+	return json.Marshal(jsonError{
+		Ops:         Ops(err),
+		Kind:        Kind(err),
+		KindText:    KindText(err),
+		Code:        CodeOf(err),
+		Category:    Category(err),
+		Level:       Level(err),
+		Msg:         Msg(err),
+		CreatedAt:   err.createdAt(),
+		User:        userOf(err),
+		Stacktrace:  Stacktrace(err),
+		Fields:      fields,
+		FieldErrors: fes,
+		Details:     DetailsOf(err),
+		Items:       batchEntries(err),
+		Resources:   ResourcesOf(err),
+		Related:     relatedCauses(err),
+		Service:     svc,
+		Version:     version,
+		Host:        host,
+		Cause:       cause,
+		Causes:      causes,
+	})
+}
+
+func relatedCauses(err error) []*jsonCause {
+	var causes []*jsonCause
+	for _, r := range RelatedOf(err) {
+		causes = append(causes, newJSONCause(Tree(r)))
+	}
+	return causes
+}
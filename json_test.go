@@ -0,0 +1,39 @@
+package errors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	fixClock(t)
+	err := errors.E("app.Handle", errors.NoStack, "handle request",
+		errors.Fields{"user": "u1", "attempt": 2, "version": "v1.2.3", "host": "web-1"},
+		errors.E("app.Sync", errors.NoStack, errors.KindServiceUnavailable, log.LevelWarn,
+			errors.E("app.Push", errors.NoStack, "push", errors.Fields{"b": 1, "a": 2}),
+			fmt.Errorf("dial: %w", stderrors.New("connection refused"))))
+
+	b, jerr := errors.MarshalJSON(err)
+	if jerr != nil {
+		t.Fatalf("MarshalJSON() error = %v", jerr)
+	}
+	for range 10 {
+		again, _ := errors.MarshalJSON(err)
+		if !bytes.Equal(again, b) {
+			t.Fatalf("MarshalJSON() = %s, want %s", again, b)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", "  "); err != nil {
+		t.Fatal(err)
+	}
+	out.WriteString("\n")
+	golden(t, "marshal_json", out.String())
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// JSONAPIError is a JSON:API error object.
+type JSONAPIError struct {
+	Status string         `json:"status"`
+	Code   string         `json:"code,omitempty"`
+	Title  string         `json:"title"`
+	Detail string         `json:"detail,omitempty"`
+	Source *JSONAPISource `json:"source,omitempty"`
+}
+
+// JSONAPISource is the source member of a JSON:API error object.
+type JSONAPISource struct {
+	Pointer string `json:"pointer"`
+}
+
+// JSONAPIErrors converts the error into JSON:API error objects.
+// Field errors are converted into objects per message with
+// pointers like "/data/attributes/email". Otherwise each layer
+// with a message becomes an object.
+// Server errors collapse into a single object without detail.
+func JSONAPIErrors(err error) []JSONAPIError {
+	if err == nil {
+		return nil
+	}
+	return jsonAPIErrors(err, Kind(err))
+}
+
+// jsonAPIErrors is like JSONAPIErrors but with the status from kind.
+func jsonAPIErrors(err error, kind KindCode) []JSONAPIError {
+	generic := JSONAPIError{Status: strconv.Itoa(int(kind)), Code: string(CodeOf(err)), Title: kind.String()}
+	if kind >= 500 || isSensitive(err) {
+		return []JSONAPIError{generic}
+	}
+
+	var objs []JSONAPIError
+	if fes, ok := hasFieldErrors(err); ok {
+		for _, field := range fes.keys() {
+			for _, msg := range fes[field] {
+				o := generic
+				o.Detail = redact(msg)
+				o.Source = &JSONAPISource{Pointer: jsonAPIPointer(field)}
+				objs = append(objs, o)
+			}
+		}
+		return objs
+	}
+
+	walk(err, func(e *appError) bool {
+		if e.msg == "" {
+			return true
+		}
+		o := generic
+		if e.kind != 0 {
+			o.Status = strconv.Itoa(int(e.kind))
+			o.Title = e.kind.String()
+		}
+		o.Detail = redact(e.msg)
+		objs = append(objs, o)
+		return true
+	})
+	if len(objs) == 0 {
+		return []JSONAPIError{generic}
+	}
+	return objs
+}
+
+func jsonAPIPointer(field string) string {
+	if strings.HasPrefix(field, "/") {
+		return field
+	}
+	return "/data/attributes/" + strings.ReplaceAll(field, ".", "/")
+}
+
+// WriteJSONAPI writes the error to w as a JSON:API document
+// with the status from its kind. Responses to canceled requests
+// are aborted as in WriteHTTP.
+func WriteJSONAPI(w http.ResponseWriter, r *http.Request, err error) {
+	kind := responseKind(r, err)
+	body, merr := json.Marshal(struct {
+		Errors []JSONAPIError `json:"errors"`
+	}{jsonAPIErrors(err, kind)})
+	if merr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	setHeaders(w, err)
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(int(kind))
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,65 @@
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestJSONAPIErrors(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want []errors.JSONAPIError
+	}{
+		"nil": {
+			err:  nil,
+			want: nil,
+		},
+		"layers": {
+			err: errors.E("app.Handle", "cannot update", errors.Code("update_failed"),
+				errors.E("app.Get", errors.KindNotFound, "no user")),
+			want: []errors.JSONAPIError{
+				{Status: "404", Code: "update_failed", Title: "Not Found", Detail: "cannot update"},
+				{Status: "404", Code: "update_failed", Title: "Not Found", Detail: "no user"},
+			},
+		},
+		"layer kinds": {
+			err: errors.E("app.Handle", errors.KindConflict, "cannot update",
+				errors.E("app.Get", errors.KindGone, "deleted user")),
+			want: []errors.JSONAPIError{
+				{Status: "409", Title: "Conflict", Detail: "cannot update"},
+				{Status: "410", Title: "Gone", Detail: "deleted user"},
+			},
+		},
+		"field errors": {
+			err: errors.E("app.Create", errors.KindUnprocessableEntity, "invalid user",
+				errors.FieldErrors{"email": {"is invalid", "is taken"}, "address.city": {"is required"}, "/data/id": {"is immutable"}}),
+			want: []errors.JSONAPIError{
+				{Status: "422", Title: "Unprocessable Entity", Detail: "is immutable", Source: &errors.JSONAPISource{Pointer: "/data/id"}},
+				{Status: "422", Title: "Unprocessable Entity", Detail: "is required", Source: &errors.JSONAPISource{Pointer: "/data/attributes/address/city"}},
+				{Status: "422", Title: "Unprocessable Entity", Detail: "is invalid", Source: &errors.JSONAPISource{Pointer: "/data/attributes/email"}},
+				{Status: "422", Title: "Unprocessable Entity", Detail: "is taken", Source: &errors.JSONAPISource{Pointer: "/data/attributes/email"}},
+			},
+		},
+		"no message": {
+			err:  errors.E("app.Get", errors.KindNotFound),
+			want: []errors.JSONAPIError{{Status: "404", Title: "Not Found"}},
+		},
+		"server error": {
+			err:  errors.E("app.Get", "connection refused"),
+			want: []errors.JSONAPIError{{Status: "500", Title: "Internal Server Error"}},
+		},
+		"sensitive": {
+			err:  errors.E("app.Get", errors.KindNotFound, errors.Sensitive, "no user alice@example.com"),
+			want: []errors.JSONAPIError{{Status: "404", Title: "Not Found"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.JSONAPIErrors(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JSONAPIErrors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
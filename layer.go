@@ -0,0 +1,53 @@
+package errors
+
+import (
+	stderrors "errors"
+
+	"go.nownabe.dev/log"
+)
+
+func outermost(err error) (*appError, bool) {
+	var e *appError
+	ok := stderrors.As(err, &e)
+	return e, ok
+}
+
+// OpOf returns the op of the outermost layer constructed by E.
+// ok is false if the op is unset.
+func OpOf(err error) (Op, bool) {
+	e, ok := outermost(err)
+	if !ok || e.op == "" {
+		return "", false
+	}
+	return e.op, true
+}
+
+// MsgOf returns the message of the outermost layer constructed by E.
+// ok is false if the message is unset.
+func MsgOf(err error) (string, bool) {
+	e, ok := outermost(err)
+	if !ok || e.msg == "" {
+		return "", false
+	}
+	return e.msg, true
+}
+
+// KindOf returns the kind of the outermost layer constructed by E.
+// ok is false if the kind is unset.
+func KindOf(err error) (KindCode, bool) {
+	e, ok := outermost(err)
+	if !ok || e.kind == 0 {
+		return 0, false
+	}
+	return e.kind, true
+}
+
+// LevelOf returns the level of the outermost layer constructed by E.
+// ok is false if the level is unset.
+func LevelOf(err error) (log.Level, bool) {
+	e, ok := outermost(err)
+	if !ok || e.level == 0 {
+		return 0, false
+	}
+	return e.level, true
+}
@@ -0,0 +1,112 @@
+package errors
+
+import (
+	stderrors "errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LineOptions selects the segments omitted by LineWith.
+type LineOptions struct {
+	OmitLevel    bool
+	OmitKind     bool
+	OmitOps      bool
+	OmitMsg      bool
+	OmitCause    bool
+	OmitLocation bool
+}
+
+// Line renders the error as a single logfmt line like
+//
+//	level=error kind=404 op=api.GetUser>store.Get msg="user not found" cause="sql: no rows" at=store.go:87
+//
+// where ops are joined by ">", cause is the innermost error
+// not constructed by E and at is the outermost location.
+// Values are quoted and escaped as needed so that
+// the line never contains newlines.
+func Line(err error) string {
+	return LineWith(err, LineOptions{})
+}
+
+// LineWith is like Line but omits the segments selected by opts.
+func LineWith(err error, opts LineOptions) string {
+	if err == nil {
+		return ""
+	}
+
+	// The cause is the first leaf. Leaves are tracked by their positions
+	// in the traversal since errors may not be comparable.
+	var (
+		ops      []string
+		cause    error
+		causeAt  int
+		at       *Frame
+		position int
+	)
+	visit(err, func(c error) bool {
+		position++
+		e, ok := c.(*appError)
+		if !ok {
+			if stderrors.Unwrap(c) == nil {
+				if _, multi := c.(interface{ Unwrap() []error }); !multi && cause == nil {
+					cause, causeAt = c, position
+				}
+			}
+			return true
+		}
+		if e.op != "" && (len(ops) == 0 || ops[len(ops)-1] != string(e.op)) {
+			ops = append(ops, string(e.op))
+		}
+		if at == nil {
+			if fr, ok := e.location(); ok {
+				at = &fr
+			}
+		}
+		return true
+	})
+
+	var b strings.Builder
+	add := func(key, value string) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(value))
+	}
+	if !opts.OmitLevel {
+		add("level", strings.ToLower(Level(err).String()))
+	}
+	if !opts.OmitKind {
+		add("kind", strconv.Itoa(int(Kind(err))))
+	}
+	if !opts.OmitOps && len(ops) > 0 {
+		add("op", strings.Join(ops, ">"))
+	}
+	if !opts.OmitMsg {
+		add("msg", Msg(err))
+	}
+	if !opts.OmitCause && cause != nil && causeAt > 1 {
+		add("cause", redact(cause.Error()))
+	}
+	if !opts.OmitLocation && at != nil {
+		add("at", filepath.Base(at.File)+":"+strconv.Itoa(at.Line))
+	}
+	return b.String()
+}
+
+// logfmtValue quotes s if it is empty or contains spaces,
+// quotes, equal signs or non-printable characters.
+func logfmtValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r == ' ' || r == '"' || r == '=' || r == '\\' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
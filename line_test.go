@@ -0,0 +1,47 @@
+package errors_test
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+// sliceError is not comparable.
+type sliceError []string
+
+func (err sliceError) Error() string { return strings.Join(err, ", ") }
+
+func TestLine(t *testing.T) {
+	err := errors.E("api.GetUser", "get user",
+		fmt.Errorf("query: %w", errors.E("store.Get", errors.KindNotFound, "user\nnot found", sliceError{"sql: no rows"})))
+	_, _, line, _ := runtime.Caller(0)
+
+	want := `level=error kind=404 op=api.GetUser>store.Get msg="get user: user not found" cause="sql: no rows" at=line_test.go:` + strconv.Itoa(line-2)
+	if got := errors.Line(err); got != want {
+		t.Errorf("Line() = %s, want %s", got, want)
+	}
+	if got := errors.LineWith(err, errors.LineOptions{OmitLevel: true, OmitKind: true, OmitLocation: true}); !strings.HasPrefix(got, "op=") || strings.Contains(got, "at=") {
+		t.Errorf("LineWith() = %s, want the segments omitted", got)
+	}
+}
+
+func TestLineUncomparable(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"top":  {sliceError{"a", "b"}, `level=error kind=500 msg="a, b"`},
+		"root": {errors.E("app.Get", sliceError{"a"}, errors.NoStack), `level=error kind=500 op=app.Get msg="Internal Server Error" cause=a`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.Line(tt.err); got != tt.want {
+				t.Errorf("Line() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"context"
+
+	"go.nownabe.dev/log"
+)
+
+// Logger is a logger used by Log.
+type Logger interface {
+	Log(level log.Level, msg string, keysAndValues ...interface{})
+}
+
+// ContextLogger is a Logger which also accepts a context.
+type ContextLogger interface {
+	Logger
+	LogContext(ctx context.Context, level log.Level, msg string, keysAndValues ...interface{})
+}
+
+// Log logs the error at its own level with Msg as the message.
+// The ops chain, stacktrace, fingerprint, TicketCode and fields
+// are attached as key/value pairs.
+// It does nothing if err is nil or denied by the Sampler set by SetSampler,
+// and logs the errors suppressed by the Sampler as SetSampler describes.
+func Log(logger Logger, err error) {
+	if err == nil {
+		return
+	}
+	msg, ok, sups := sampled(err)
+	for _, sup := range sups {
+		logger.Log(sup.Level, suppressedMsg(sup.Msg, sup.Count), suppressedKeysAndValues(sup)...)
+	}
+	if !ok {
+		return
+	}
+	logger.Log(Level(err), msg, logKeysAndValues(err)...)
+}
+
+// LogContext is like Log but passes ctx to
+// the logger if it implements ContextLogger.
+func LogContext(ctx context.Context, logger Logger, err error) {
+	if err == nil {
+		return
+	}
+	msg, ok, sups := sampled(err)
+	cl, isCtx := logger.(ContextLogger)
+	for _, sup := range sups {
+		if isCtx {
+			cl.LogContext(ctx, sup.Level, suppressedMsg(sup.Msg, sup.Count), suppressedKeysAndValues(sup)...)
+		} else {
+			logger.Log(sup.Level, suppressedMsg(sup.Msg, sup.Count), suppressedKeysAndValues(sup)...)
+		}
+	}
+	if !ok {
+		return
+	}
+	if isCtx {
+		cl.LogContext(ctx, Level(err), msg, logKeysAndValues(err)...)
+		return
+	}
+	logger.Log(Level(err), msg, logKeysAndValues(err)...)
+}
+
+func logKeysAndValues(err error) []interface{} {
+	fields := FieldsOf(err)
+	fp := Fingerprint(err)
+	kvs := make([]interface{}, 0, 8+len(fields)*2)
+	kvs = append(kvs, "ops", Ops(err), "stacktrace", Stacktrace(err), "fingerprint", fp, "ticket", ticketCode(fp))
+	for _, k := range fields.keys() {
+		kvs = append(kvs, k, fields[k])
+	}
+	return kvs
+}
+
+func suppressedKeysAndValues(sup Suppressed) []interface{} {
+	return []interface{}{"fingerprint", sup.Fingerprint, "ticket", ticketCode(sup.Fingerprint)}
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+)
+
+// Match reports whether got matches template.
+// See MatchDiff for the rules.
+func Match(template, got error) bool {
+	return MatchDiff(template, got) == ""
+}
+
+// MatchDiff returns a human-readable explanation of the first mismatch
+// between template and got, or "" if they match.
+// Only the fields set on the template built with E are compared,
+// where ops derived from function names are treated as unset:
+// op, kind and level must be equal, msg must be a substring,
+// and a wrapped error is matched recursively if it was built with E
+// or with errors.Is otherwise. Kind and level are compared with
+// the values effective at the layer.
+func MatchDiff(template, got error) string {
+	if template == nil {
+		if got == nil {
+			return ""
+		}
+		return fmt.Sprintf("got %v, want nil", got)
+	}
+	if got == nil {
+		return fmt.Sprintf("got nil, want %v", template)
+	}
+
+	t, ok := template.(*appError)
+	if !ok {
+		if stderrors.Is(got, template) {
+			return ""
+		}
+		return fmt.Sprintf("got %v, want an error matching %v", got, template)
+	}
+
+	g := firstAppError(got)
+	if g == nil {
+		return fmt.Sprintf("got %v, want an error constructed by E", got)
+	}
+	if t.op != "" && !t.derivedOp && t.op != g.op {
+		return fmt.Sprintf("op: got %q, want %q", g.op, t.op)
+	}
+	if t.kind != 0 && Kind(g) != t.kind {
+		return fmt.Sprintf("op %q: kind: got %d, want %d", g.op, Kind(g), t.kind)
+	}
+	if t.level != 0 && Level(g) != t.level {
+		return fmt.Sprintf("op %q: level: got %v, want %v", g.op, Level(g), t.level)
+	}
+	if t.msg != "" && !strings.Contains(g.msg, t.msg) {
+		return fmt.Sprintf("op %q: msg: got %q, want containing %q", g.op, g.msg, t.msg)
+	}
+	if t.err == nil {
+		return ""
+	}
+	if g.err == nil {
+		return fmt.Sprintf("op %q: got no wrapped error, want %v", g.op, t.err)
+	}
+	return MatchDiff(t.err, g.err)
+}
+
+func firstAppError(err error) *appError {
+	var first *appError
+	walk(err, func(e *appError) bool {
+		first = e
+		return false
+	})
+	return first
+}
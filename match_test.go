@@ -0,0 +1,64 @@
+package errors_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestMatchDiff(t *testing.T) {
+	got := errors.E("app.Handle", errors.KindNotFound,
+		errors.E("app.Get", log.LevelWarn, "no user u1", fmt.Errorf("query: %w", sql.ErrNoRows)))
+	tests := map[string]struct {
+		template error
+		got      error
+		want     string
+	}{
+		"nil":            {nil, nil, ""},
+		"want nil":       {nil, sql.ErrNoRows, "got sql: no rows in result set, want nil"},
+		"got nil":        {sql.ErrNoRows, nil, "got nil, want sql: no rows in result set"},
+		"op":             {errors.E("app.Handle"), got, ""},
+		"derived op":     {errors.E("", errors.KindNotFound), got, ""},
+		"op mismatch":    {errors.E("app.Get"), got, `op: got "app.Handle", want "app.Get"`},
+		"kind mismatch":  {errors.E("app.Handle", errors.KindGone), got, `op "app.Handle": kind: got 404, want 410`},
+		"level mismatch": {errors.E("app.Handle", log.LevelInfo), got, fmt.Sprintf(`op "app.Handle": level: got %v, want %v`, log.LevelWarn, log.LevelInfo)},
+		"wrapped": {
+			errors.E("app.Handle", errors.KindNotFound, errors.E("app.Get", "no user", sql.ErrNoRows)),
+			got,
+			"",
+		},
+		"msg mismatch": {
+			errors.E("app.Handle", errors.E("app.Get", "no item")),
+			got,
+			`op "app.Get": msg: got "no user u1", want containing "no item"`,
+		},
+		"wrapped mismatch": {
+			errors.E("app.Handle", errors.E("app.Get", sql.ErrTxDone)),
+			got,
+			"got query: sql: no rows in result set, want an error matching sql: transaction has already been committed or rolled back",
+		},
+		"no wrapped error": {
+			errors.E("app.Handle", errors.E("app.Get", errors.E("db.Query"))),
+			errors.E("app.Handle", errors.E("app.Get")),
+			`op "app.Get": got no wrapped error, want db.Query`,
+		},
+		"plain": {
+			errors.E("app.Handle", errors.E("app.Get")),
+			sql.ErrNoRows,
+			"got sql: no rows in result set, want an error constructed by E",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.MatchDiff(tt.template, tt.got); got != tt.want {
+				t.Errorf("MatchDiff() = %q, want %q", got, tt.want)
+			}
+			if got := errors.Match(tt.template, tt.got); got != (tt.want == "") {
+				t.Errorf("Match() = %t, want %t", got, tt.want == "")
+			}
+		})
+	}
+}
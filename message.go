@@ -0,0 +1,20 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+// Message is a formatted message accepted by E.
+type Message struct {
+	text    string
+	wrapped error
+}
+
+// Msgf formats a message for E like fmt.Sprintf.
+// An error referenced by %w is wrapped by the constructed error
+// unless another error is passed to E.
+func Msgf(format string, args ...interface{}) Message {
+	err := fmt.Errorf(format, args...)
+	return Message{text: err.Error(), wrapped: stderrors.Unwrap(err)}
+}
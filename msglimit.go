@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+type msgLimit struct {
+	maxLen           int
+	collapseNewlines bool
+}
+
+var msgLimitConfig atomic.Value
+
+func init() {
+	msgLimitConfig.Store(msgLimit{maxLen: 1 << 10, collapseNewlines: true})
+}
+
+// SetMsgLimits sets the maximum length in bytes of messages returned
+// by Msg, JoinMsg and ClientMsg, and whether newlines in them are
+// collapsed into spaces. Longer messages are truncated with an ellipsis
+// and the count of omitted bytes. Zero maxLen means no limit.
+// They default to 1 KiB and true. %+v is never limited.
+func SetMsgLimits(maxLen int, collapseNewlines bool) {
+	msgLimitConfig.Store(msgLimit{maxLen: maxLen, collapseNewlines: collapseNewlines})
+}
+
+// limitMsg applies the limits to the redacted message s.
+func limitMsg(s string) string {
+	c := msgLimitConfig.Load().(msgLimit)
+	if c.collapseNewlines && strings.ContainsAny(s, "\r\n") {
+		s = strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+	}
+	if c.maxLen <= 0 || len(s) <= c.maxLen {
+		return s
+	}
+	n := c.maxLen
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return fmt.Sprintf("%s… (%d bytes omitted)", s[:n], len(s)-n)
+}
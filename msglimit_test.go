@@ -0,0 +1,48 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestSetMsgLimits(t *testing.T) {
+	defer errors.SetMsgLimits(1<<10, true)
+
+	tests := map[string]struct {
+		maxLen   int
+		collapse bool
+		msg      string
+		want     string
+	}{
+		"short":     {16, true, "no user", "no user"},
+		"ascii":     {8, true, "no user with the id", "no user … (11 bytes omitted)"},
+		"multibyte": {8, true, "ユーザーが見つかりません", "ユー… (30 bytes omitted)"},
+		"rune edge": {9, true, "ユーザーが見つかりません", "ユーザ… (27 bytes omitted)"},
+		"collapse":  {0, true, "no user\nwith\r\nthe id", "no user with the id"},
+		"newlines":  {0, false, "no user\nwith the id", "no user\nwith the id"},
+		"unlimited": {0, true, strings.Repeat("a", 2<<10), strings.Repeat("a", 2<<10)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			errors.SetMsgLimits(tt.maxLen, tt.collapse)
+			err := errors.E("app.Get", errors.KindNotFound, tt.msg)
+			got := errors.Msg(err)
+			if got != tt.want {
+				t.Errorf("Msg() = %q, want %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("Msg() = %q, want valid UTF-8", got)
+			}
+			if got := errors.ClientMsg(err); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("ClientMsg() = %q, want %q and the ticket", got, tt.want)
+			}
+			if end := tt.msg[strings.LastIndex(tt.msg, " ")+1:]; !strings.Contains(fmt.Sprintf("%+v", err), end) {
+				t.Errorf("%%+v does not have the end of the message %q", end)
+			}
+		})
+	}
+}
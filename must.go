@@ -0,0 +1,19 @@
+package errors
+
+// Must returns v if err is nil and panics otherwise.
+// The panic value is an error wrapping err with KindUnexpected
+// and the location of the caller of Must.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(newError(1, "", []interface{}{err, KindUnexpected}))
+	}
+	return v
+}
+
+// Check panics if err is not nil.
+// The panic value is an error wrapping err with op and KindUnexpected.
+func Check(op Op, err error) {
+	if err != nil {
+		panic(newError(1, op, []interface{}{err, KindUnexpected}))
+	}
+}
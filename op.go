@@ -0,0 +1,196 @@
+package errors
+
+import (
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	opPathSegment = regexp.MustCompile(`^[\w.~-]+$`)
+	opFunc        = regexp.MustCompile(`^\w+(\.\w+)+$`)
+	opRoute       = regexp.MustCompile(`^[A-Z]+ \S*/\S*$`)
+)
+
+// String returns the op as a string.
+func (op Op) String() string {
+	return string(op)
+}
+
+// Validate reports whether the op has a shape like "pkg.Func",
+// "pkg.Type.Method" or "path/to/pkg.Func", or is a route
+// like "GET /users/{id}" returned by OpFromRequest.
+func (op Op) Validate() error {
+	if opRoute.MatchString(string(op)) {
+		return nil
+	}
+	segs := strings.Split(string(op), "/")
+	for _, s := range segs[:len(segs)-1] {
+		if !opPathSegment.MatchString(s) {
+			return &invalidOpError{op: op}
+		}
+	}
+	if !opFunc.MatchString(segs[len(segs)-1]) {
+		return &invalidOpError{op: op}
+	}
+	return nil
+}
+
+// invalidOpError is returned by Op.Validate. It is not constructed
+// by E so that validation never reaches the hooks or Stats.
+type invalidOpError struct {
+	op Op
+}
+
+func (err *invalidOpError) Error() string {
+	return "errors: invalid op " + strconv.Quote(string(err.op))
+}
+
+// OpOfCaller returns the op derived from the name of the calling
+// function like E does for an empty op, e.g.
+//
+//	func (s *Store) Get(id string) error {
+//		op := errors.OpOfCaller()
+//		...
+//	}
+func OpOfCaller() Op {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	return funcOp(fn.Name())
+}
+
+var stripReceiver atomic.Bool
+
+// SetStripReceiver sets whether ops derived from function names
+// omit the receiver type names of methods.
+func SetStripReceiver(strip bool) {
+	stripReceiver.Store(strip)
+}
+
+// funcOp derives an op like "pkg.Func" or "pkg.Type.Method"
+// from a function name reported by the runtime.
+func funcOp(function string) Op {
+	function = stripTypeArgs(function)
+	if i := strings.LastIndex(function, "/"); i >= 0 {
+		function = function[i+1:]
+	}
+	parts := strings.Split(function, ".")
+	if len(parts) > 2 && strings.HasPrefix(parts[1], "(") {
+		if stripReceiver.Load() {
+			parts = append(parts[:1], parts[2:]...)
+		} else {
+			parts[1] = strings.Trim(parts[1], "(*)")
+		}
+	}
+	return Op(strings.Join(parts, "."))
+}
+
+// Here returns the op that makes E derive the op from the name of
+// the calling function. It is equivalent to the empty op but reads
+// better at call sites.
+func Here() Op {
+	return ""
+}
+
+// stripTypeArgs removes "[...]" of generic functions and types.
+func stripTypeArgs(function string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range function {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// split splits the op into the package path and the rest
+// at the first "." after the last "/".
+// Single-segment ops have no package.
+func (op Op) split() (pkg, fn string) {
+	s := string(op)
+	start := op.lastElem()
+	i := strings.IndexByte(s[start:], '.')
+	if i < 0 {
+		return "", s
+	}
+	return s[:start+i], s[start+i+1:]
+}
+
+// Package returns the package part of the op,
+// e.g. "store" of "store.Store.Get".
+func (op Op) Package() string {
+	pkg, _ := op.split()
+	return pkg
+}
+
+// Function returns the function part of the op,
+// e.g. "Store.Get" of "store.Store.Get".
+func (op Op) Function() string {
+	_, fn := op.split()
+	return fn
+}
+
+// lastElem returns the index of the last element of the package path,
+// where dots start to separate segments. Dots before it belong to
+// path elements such as "example.com".
+func (op Op) lastElem() int {
+	return strings.LastIndex(string(op), "/") + 1
+}
+
+// hasPrefix reports whether the op starts with prefix
+// followed by a segment boundary or the end.
+func (op Op) hasPrefix(prefix string) bool {
+	s := string(op)
+	if prefix == "" || !strings.HasPrefix(s, prefix) {
+		return false
+	}
+	if len(s) == len(prefix) {
+		return true
+	}
+	switch s[len(prefix)] {
+	case '/':
+		return true
+	case '.':
+		return len(prefix) >= op.lastElem()
+	}
+	return false
+}
+
+// HasOpPrefix reports whether any op in err's chain has the prefix
+// on a segment boundary: "billing" matches "billing.invoice.Create"
+// but not "billingx.Foo".
+func HasOpPrefix(err error, prefix string) bool {
+	found := false
+	walk(err, func(e *appError) bool {
+		found = e.op.hasPrefix(prefix)
+		return !found
+	})
+	return found
+}
+
+// FilterOps returns the ops in err's chain which have the prefix
+// in the sense of HasOpPrefix.
+func FilterOps(err error, prefix string) []Op {
+	var ops []Op
+	walk(err, func(e *appError) bool {
+		if e.op.hasPrefix(prefix) {
+			ops = append(ops, e.op)
+		}
+		return true
+	})
+	return ops
+}
@@ -0,0 +1,156 @@
+package errors_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func genericOp[T any](T) errors.Op {
+	return errors.OpOfCaller()
+}
+
+var globalClosure = func() errors.Op { return errors.OpOfCaller() }
+
+type box[T any] struct{}
+
+func (*box[T]) op() errors.Op {
+	return errors.OpOfCaller()
+}
+
+func TestOpOfCaller(t *testing.T) {
+	closure := func() errors.Op { return errors.OpOfCaller() }
+	nested := func() errors.Op {
+		return func() errors.Op { return errors.OpOfCaller() }()
+	}
+	tests := map[string]struct {
+		op   errors.Op
+		want errors.Op
+	}{
+		"function":               {errors.OpOfCaller(), "errors_test.TestOpOfCaller"},
+		"closure":                {closure(), "errors_test.TestOpOfCaller.func1"},
+		"nested closure":         {nested(), "errors_test.TestOpOfCaller.func2.func1"},
+		"package closure":        {globalClosure(), "errors_test.init.func1"},
+		"generic function":       {genericOp(1), "errors_test.genericOp"},
+		"method of generic type": {(&box[string]{}).op(), "errors_test.box.op"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.op != tt.want {
+				t.Errorf("OpOfCaller() = %q, want %q", tt.op, tt.want)
+			}
+			if err := tt.op.Validate(); err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestOpValidate(t *testing.T) {
+	tests := map[errors.Op]bool{
+		"store.Get":                 true,
+		"store.Store.Get":           true,
+		"example.com/app/store.Get": true,
+		"GET /users/{id}":           true,
+		"Get":                       false,
+		"store.":                    false,
+		"store..Get":                false,
+		"example.com/a b/store.Get": false,
+		"store.Get ":                false,
+	}
+	for op, valid := range tests {
+		if err := op.Validate(); (err == nil) != valid {
+			t.Errorf("Validate(%q) = %v, want valid %v", op, err, valid)
+		}
+	}
+}
+
+func TestOpValidateSilent(t *testing.T) {
+	var hooked bool
+	remove := errors.OnError(func(errors.Error) { hooked = true })
+	defer remove()
+	errors.ResetStats()
+
+	err := errors.Op("Get").Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want an error")
+	}
+	if _, ok := err.(errors.Error); ok {
+		t.Error("Validate() returned an error constructed by E")
+	}
+	if hooked {
+		t.Error("Validate() fired the hooks")
+	}
+	if s := errors.Stats(); len(s) != 0 {
+		t.Errorf("Stats() = %v, want none", s)
+	}
+}
+
+func TestStrictOps(t *testing.T) {
+	errors.SetStrict(true)
+	defer errors.SetStrict(false)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("E did not panic on a malformed op")
+			}
+		}()
+		_ = errors.E("Get", errors.KindNotFound)
+	}()
+
+	err := errors.E("store.Get", errors.KindNotFound)
+	if got := errors.Ops(errors.E("app.Handle", err)); len(got) != 2 {
+		t.Errorf("Ops() = %v, want 2 ops", got)
+	}
+	if got := errors.Ops(errors.E("", err)); len(got) != 2 {
+		t.Errorf("Ops() = %v, want the derived op", got)
+	}
+}
+
+func TestOpSplit(t *testing.T) {
+	tests := map[errors.Op][2]string{
+		"Get":                       {"", "Get"},
+		"":                          {"", ""},
+		"store.Get":                 {"store", "Get"},
+		"store.Store.Get":           {"store", "Store.Get"},
+		"example.com/app/store.Get": {"example.com/app/store", "Get"},
+		"example.com/app":           {"", "example.com/app"},
+		"store.":                    {"store", ""},
+	}
+	for op, want := range tests {
+		if got := [2]string{op.Package(), op.Function()}; got != want {
+			t.Errorf("%q: Package(), Function() = %q, want %q", op, got, want)
+		}
+	}
+}
+
+func TestHasOpPrefix(t *testing.T) {
+	err := fmt.Errorf("handle: %w", errors.E("api.Handle",
+		errors.E("billing.invoice.Create", errors.E("example.com/app/store.Get"))))
+	tests := map[string][]errors.Op{
+		"billing":               {"billing.invoice.Create"},
+		"billing.invoice":       {"billing.invoice.Create"},
+		"billing.inv":           nil,
+		"billingx":              nil,
+		"api.Handle":            {"api.Handle"},
+		"example.com/app":       {"example.com/app/store.Get"},
+		"example":               nil,
+		"example.com":           {"example.com/app/store.Get"},
+		"example.com/app/store": {"example.com/app/store.Get"},
+		"":                      nil,
+	}
+	for prefix, want := range tests {
+		if got := errors.HasOpPrefix(err, prefix); got != (want != nil) {
+			t.Errorf("HasOpPrefix(%q) = %v, want %v", prefix, got, want != nil)
+		}
+		if got := errors.FilterOps(err, prefix); !reflect.DeepEqual(got, want) {
+			t.Errorf("FilterOps(%q) = %q, want %q", prefix, got, want)
+		}
+	}
+	if errors.HasOpPrefix(errors.E("Get"), "Get.") {
+		t.Error(`HasOpPrefix("Get.") = true for the single-segment op "Get"`)
+	}
+}
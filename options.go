@@ -0,0 +1,43 @@
+package errors
+
+import "go.nownabe.dev/log"
+
+// Option is a typed option of Build.
+type Option func(b *builder)
+
+// Build constructs an error like E with typed options.
+func Build(op Op, opts ...Option) error {
+	e := newAppError(op)
+	b := builder{e: e}
+	for _, opt := range opts {
+		opt(&b)
+	}
+	e.finish(1, b.errs, b.wrapped)
+	notify(e)
+	return e
+}
+
+// OptErr wraps err. Nil errors are ignored.
+func OptErr(err error) Option {
+	return func(b *builder) { b.add(err) }
+}
+
+// OptMsg sets the message.
+func OptMsg(msg string) Option {
+	return func(b *builder) { b.add(msg) }
+}
+
+// OptKind sets the kind.
+func OptKind(kind KindCode) Option {
+	return func(b *builder) { b.add(kind) }
+}
+
+// OptLevel sets the level.
+func OptLevel(level log.Level) Option {
+	return func(b *builder) { b.add(level) }
+}
+
+// OptField sets the field.
+func OptField(key string, value interface{}) Option {
+	return func(b *builder) { b.add(Fields{key: value}) }
+}
@@ -0,0 +1,38 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestBuild(t *testing.T) {
+	fixClock(t)
+	root := stderrors.New("sql: no rows in result set")
+
+	// Each pair is constructed on one line so that the locations agree.
+	tests := map[string][2]error{
+		"leaf": func() [2]error {
+			return [2]error{errors.E("app.Get", "no user"), errors.Build("app.Get", errors.OptMsg("no user"))}
+		}(),
+		"wrap": func() [2]error {
+			return [2]error{errors.E("app.Get", root, errors.KindNotFound, log.LevelWarn, "get user", errors.Fields{"id": 1}), errors.Build("app.Get", errors.OptErr(root), errors.OptKind(errors.KindNotFound), errors.OptLevel(log.LevelWarn), errors.OptMsg("get user"), errors.OptField("id", 1))}
+		}(),
+		"nil error": func() [2]error {
+			return [2]error{errors.E("app.Get", error(nil), "failed"), errors.Build("app.Get", errors.OptErr(nil), errors.OptMsg("failed"))}
+		}(),
+	}
+	for name, pair := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, b := pair[0], pair[1]
+			for _, format := range []string{"%s", "%v", "%+v"} {
+				if got, want := fmt.Sprintf(format, b), fmt.Sprintf(format, e); got != want {
+					t.Errorf("Sprintf(%q) of Build = %q, want %q", format, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Package otelerrors integrates go.nownabe.dev/errors with OpenTelemetry.
+package otelerrors // import "go.nownabe.dev/errors/otelerrors"
+
+import (
+	"context"
+
+	"go.nownabe.dev/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the field key of trace IDs.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the field key of span IDs.
+	SpanIDKey = "span_id"
+)
+
+// TraceIDExtractor extracts the trace ID of the active span.
+func TraceIDExtractor(ctx context.Context) (string, interface{}, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return "", nil, false
+	}
+	return TraceIDKey, sc.TraceID().String(), true
+}
+
+// SpanIDExtractor extracts the span ID of the active span.
+func SpanIDExtractor(ctx context.Context) (string, interface{}, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasSpanID() {
+		return "", nil, false
+	}
+	return SpanIDKey, sc.SpanID().String(), true
+}
+
+// RegisterExtractors registers TraceIDExtractor and SpanIDExtractor
+// to be consulted by errors.EC.
+func RegisterExtractors() {
+	errors.RegisterContextExtractor(TraceIDExtractor)
+	errors.RegisterContextExtractor(SpanIDExtractor)
+}
@@ -0,0 +1,48 @@
+package otelerrors
+
+import (
+	"context"
+	"fmt"
+
+	"go.nownabe.dev/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "go.nownabe.dev/errors/otelerrors"
+
+// Record records err on span. The root cause is recorded as the
+// exception event with the kind, ops, level and creation location
+// as attributes. Server error kinds set the span status to error and
+// client error kinds leave it unset.
+func Record(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	kind := errors.Kind(err)
+	attrs := []attribute.KeyValue{
+		attribute.Int("error.kind", int(kind)),
+		attribute.StringSlice("error.ops", errors.Ops(err)),
+		attribute.String("error.level", fmt.Sprint(errors.Level(err))),
+	}
+	if frames := errors.Frames(err); len(frames) > 0 {
+		attrs = append(attrs,
+			attribute.String("code.function", frames[0].Function),
+			attribute.String("code.filepath", frames[0].File),
+			attribute.Int("code.lineno", frames[0].Line),
+		)
+	}
+
+	span.RecordError(errors.Root(err), trace.WithAttributes(attrs...))
+	if kind >= 500 {
+		span.SetStatus(codes.Error, kind.String())
+	}
+}
+
+// StartSpanOp starts a span named after op with the global tracer provider.
+func StartSpanOp(ctx context.Context, op errors.Op) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, string(op))
+}
@@ -0,0 +1,90 @@
+package otelerrors_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/errors/otelerrors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setup(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return sr
+}
+
+func TestRecord(t *testing.T) {
+	root := stderrors.New("connection refused")
+	tests := []struct {
+		name   string
+		err    error
+		status codes.Code
+	}{
+		{"server error", errors.E("app.Handle", errors.E("app.Get", errors.KindUnexpected, root)), codes.Error},
+		{"client error", errors.E("app.Handle", errors.E("app.Get", errors.KindNotFound, root)), codes.Unset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := setup(t)
+			_, span := otelerrors.StartSpanOp(context.Background(), "app.Handle")
+			otelerrors.Record(span, tt.err)
+			span.End()
+
+			spans := sr.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			s := spans[0]
+			if s.Name() != "app.Handle" {
+				t.Errorf("span name = %q, want %q", s.Name(), "app.Handle")
+			}
+			if s.Status().Code != tt.status {
+				t.Errorf("status = %v, want %v", s.Status().Code, tt.status)
+			}
+			events := s.Events()
+			if len(events) != 1 || events[0].Name != "exception" {
+				t.Fatalf("events = %v, want one exception", events)
+			}
+			attrs := map[attribute.Key]attribute.Value{}
+			for _, a := range events[0].Attributes {
+				attrs[a.Key] = a.Value
+			}
+			if got := attrs["exception.message"].AsString(); got != root.Error() {
+				t.Errorf("exception.message = %q, want the root cause %q", got, root.Error())
+			}
+			if got := attrs["error.kind"].AsInt64(); got != int64(errors.Kind(tt.err)) {
+				t.Errorf("error.kind = %d, want %d", got, errors.Kind(tt.err))
+			}
+			if got := attrs["error.ops"].AsStringSlice(); len(got) != 2 || got[0] != "app.Handle" || got[1] != "app.Get" {
+				t.Errorf("error.ops = %q", got)
+			}
+			if got := attrs["error.level"].AsString(); got == "" {
+				t.Error("error.level is empty")
+			}
+			if got := attrs["code.function"].AsString(); got == "" {
+				t.Error("code.function is empty")
+			}
+		})
+	}
+}
+
+func TestRecordNil(t *testing.T) {
+	sr := setup(t)
+	_, span := otelerrors.StartSpanOp(context.Background(), "app.Handle")
+	otelerrors.Record(span, nil)
+	span.End()
+	if events := sr.Ended()[0].Events(); len(events) != 0 {
+		t.Errorf("events = %v, want none", events)
+	}
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"go.nownabe.dev/log"
+)
+
+// FromPanic converts a value recovered from a panic into an error
+// with KindUnexpected and LevelCritical. It captures the stack of
+// the panicking goroutine, so it must be called in the deferred
+// function during panicking. It returns nil if recovered is nil.
+func FromPanic(op Op, recovered interface{}) error {
+	if recovered == nil {
+		return nil
+	}
+	return fromPanic(op, recovered)
+}
+
+// Recover recovers a panic and stores it into *errp as an error
+// constructed by FromPanic. It must be deferred directly:
+//
+//	defer errors.Recover(op, &err)
+//
+// Panics with http.ErrAbortHandler are not recovered
+// so that net/http can abort the response.
+func Recover(op Op, errp *error) {
+	if r := recover(); r != nil {
+		if r == http.ErrAbortHandler {
+			panic(r)
+		}
+		*errp = fromPanic(op, r)
+	}
+}
+
+func fromPanic(op Op, recovered interface{}) error {
+	err, ok := recovered.(error)
+	if !ok {
+		err = stderrors.New(fmt.Sprint(recovered))
+	}
+	e := &appError{op: op, err: err, kind: KindUnexpected, level: log.LevelCritical, created: now()}
+
+	pcs := make([]uintptr, stackDepth()+8)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			pcs = pcs[i+1:]
+			break
+		}
+	}
+	// Runtime errors are raised by frames such as runtime.sigpanic
+	// and runtime.panicmem above the panicking code.
+	for len(pcs) > 0 {
+		fn := runtime.FuncForPC(pcs[0] - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+			break
+		}
+		pcs = pcs[1:]
+	}
+	e.frames = pcs
+	notify(e)
+
+	return e
+}
@@ -0,0 +1,73 @@
+package errors_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+type node struct{ next *node }
+
+//go:noinline
+func dereference(n *node) *node {
+	return n.next
+}
+
+//go:noinline
+func index(s []int, i int) int {
+	return s[i]
+}
+
+//go:noinline
+func explicit() {
+	panic("boom")
+}
+
+func recovered(f func()) (err error) {
+	defer errors.Recover("app.Run", &err)
+	f()
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+		top  string
+	}{
+		{"nil dereference", func() { dereference(nil) }, ".dereference"},
+		{"index out of range", func() { index(nil, 1) }, ".index"},
+		{"explicit", explicit, ".explicit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := recovered(tt.f)
+			if err == nil {
+				t.Fatal("Recover did not store the panic")
+			}
+			if k := errors.Kind(err); k != errors.KindUnexpected {
+				t.Errorf("Kind() = %d, want %d", k, errors.KindUnexpected)
+			}
+			if l := errors.Level(err); l != log.LevelCritical {
+				t.Errorf("Level() = %v, want %v", l, log.LevelCritical)
+			}
+			pcs := errors.StackPCs(err)
+			if len(pcs) == 0 {
+				t.Fatal("no program counters captured")
+			}
+			fr, _ := runtime.CallersFrames(pcs).Next()
+			if !strings.HasSuffix(fr.Function, tt.top) {
+				t.Errorf("top frame = %s, want the panicking function %s", fr.Function, tt.top)
+			}
+		})
+	}
+}
+
+func TestFromPanicNil(t *testing.T) {
+	if err := errors.FromPanic("app.Run", nil); err != nil {
+		t.Errorf("FromPanic(nil) = %v, want nil", err)
+	}
+}
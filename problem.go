@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Problem is an RFC 9457 problem details object.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+var problemTypes = struct {
+	sync.RWMutex
+	m map[KindCode]string
+}{m: map[KindCode]string{}}
+
+// RegisterProblemType registers the type URI used for the kind.
+func RegisterProblemType(kind KindCode, uri string) {
+	problemTypes.Lock()
+	defer problemTypes.Unlock()
+	problemTypes.m[kind] = uri
+}
+
+func problemType(kind KindCode) string {
+	problemTypes.RLock()
+	defer problemTypes.RUnlock()
+	if uri, ok := problemTypes.m[kind]; ok {
+		return uri
+	}
+	return "about:blank"
+}
+
+// ProblemDetails converts the error into a Problem.
+// Server error kinds use the kind text as detail and omit the fields
+// so that internal messages and values are not exposed.
+// The "ticket" extension is TicketCode.
+func ProblemDetails(err error) Problem {
+	return problemDetails(err, Kind(err))
+}
+
+// problemDetails is like ProblemDetails but with the status from kind.
+func problemDetails(err error, kind KindCode) Problem {
+	p := Problem{
+		Type:       problemType(kind),
+		Title:      kind.String(),
+		Status:     int(kind),
+		Detail:     kind.String(),
+		Extensions: map[string]interface{}{},
+	}
+	if kind < 500 {
+		p.Detail = publicMsg(err)
+		for k, v := range FieldsOf(err) {
+			p.Extensions[k] = v
+		}
+	}
+	p.Extensions["ops"] = Ops(err)
+	if m, ok := MismatchOf(err); ok {
+		p.Extensions["expected"] = renderValue(m.Expected)
+		p.Extensions["actual"] = renderValue(m.Actual)
+	}
+	if kind, id, ok := ResourceOf(err); ok {
+		p.Extensions["resource"] = ResourceRef{Kind: kind, ID: id}
+	}
+	if code := CodeOf(err); code != "" {
+		p.Extensions["code"] = code
+	}
+	p.Extensions["ticket"] = TicketCode(err)
+	if fes, ok := hasFieldErrors(err); ok {
+		p.Extensions["errors"] = fes
+	}
+	if details := DetailsOf(err); details != nil {
+		p.Extensions["details"] = details
+	}
+	if items := batchEntries(err); items != nil {
+		p.Extensions["items"] = items
+	}
+	return p
+}
+
+// MarshalJSON implements json.Marshaler.
+// Extensions are encoded as top-level members.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// WriteProblem writes the error as application/problem+json.
+// Responses to canceled requests are aborted as in WriteHTTP.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	p := problemDetails(err, responseKind(r, err))
+	body, merr := json.Marshal(p)
+	if merr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	setHeaders(w, err)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(body)
+}
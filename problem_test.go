@@ -0,0 +1,39 @@
+package errors_test
+
+import (
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestProblemDetails(t *testing.T) {
+	fields := errors.Fields{"user_id": "u1", "url": "http://billing.internal/charge"}
+	tests := map[string]struct {
+		err        error
+		wantDetail string
+		wantFields bool
+	}{
+		"client error": {errors.E("app.Get", errors.KindNotFound, "no user", fields), "no user", true},
+		"server error": {errors.E("app.Get", errors.KindServiceUnavailable, "billing is down", fields), "Service Unavailable", false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := errors.ProblemDetails(tt.err)
+
+			if int(errors.Kind(tt.err)) != p.Status {
+				t.Errorf("Status = %d, want %d", p.Status, errors.Kind(tt.err))
+			}
+			if p.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", p.Detail, tt.wantDetail)
+			}
+			for k := range fields {
+				if _, ok := p.Extensions[k]; ok != tt.wantFields {
+					t.Errorf("Extensions[%q] present = %v, want %v", k, ok, tt.wantFields)
+				}
+			}
+			if p.Extensions["ticket"] != errors.TicketCode(tt.err) {
+				t.Errorf("Extensions[ticket] = %v, want %s", p.Extensions["ticket"], errors.TicketCode(tt.err))
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package errors
+
+import "sync/atomic"
+
+// Sensitivity marks whether the messages of an error may be exposed.
+type Sensitivity int
+
+// Sensitive can be passed to E to replace the whole message
+// of the chain with the kind text in Msg, ClientMsg and renderers.
+const Sensitive Sensitivity = 1
+
+var redactor atomic.Value
+
+// SetRedactor sets fn to redact messages returned by Msg and ClientMsg
+// and rendered by JSON marshaling and the HTTP writers.
+// Error and %+v are not redacted since they are for diagnostics.
+// Passing nil disables redaction.
+func SetRedactor(fn func(string) string) {
+	if fn == nil {
+		fn = func(s string) string { return s }
+	}
+	redactor.Store(fn)
+}
+
+func redact(s string) string {
+	if fn, ok := redactor.Load().(func(string) string); ok {
+		return fn(s)
+	}
+	return s
+}
+
+func isSensitive(err error) bool {
+	found := false
+	walk(err, func(e *appError) bool {
+		found = e.sensitivity == Sensitive
+		return !found
+	})
+	return found
+}
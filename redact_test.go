@@ -0,0 +1,47 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestSensitive(t *testing.T) {
+	inner := errors.E("app.Get", errors.KindNotFound, errors.Sensitive, "no user alice@example.com")
+	tests := map[string]error{
+		"appError":    errors.E("app.Handle", "handle", inner),
+		"fmt wrapper": fmt.Errorf("handle: %w", inner),
+	}
+	for name, err := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.Msg(err); got != "Not Found" {
+				t.Errorf("Msg() = %q, want the kind text", got)
+			}
+			if got := errors.ClientMsg(err); strings.Contains(got, "alice") {
+				t.Errorf("ClientMsg() = %q, want no message", got)
+			}
+			if got := err.Error(); !strings.Contains(got, "alice") {
+				t.Errorf("Error() = %q, want the message for diagnostics", got)
+			}
+		})
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	calls := 0
+	errors.SetRedactor(func(s string) string {
+		calls++
+		return strings.ReplaceAll(s, "alice@example.com", "***")
+	})
+	defer errors.SetRedactor(nil)
+
+	err := errors.E("app.Handle", "handle alice@example.com", errors.E("app.Get", "no user alice@example.com"))
+	if got, want := errors.Msg(err), "handle ***: no user ***"; got != want {
+		t.Errorf("Msg() = %q, want %q", got, want)
+	}
+	if calls != 1 {
+		t.Errorf("redactor called %d times, want 1", calls)
+	}
+}
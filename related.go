@@ -0,0 +1,47 @@
+package errors
+
+import stderrors "errors"
+
+// RelatedErrors are secondary errors which can be passed to E,
+// such as a failure of rollback after the primary failure.
+// They are not part of the chain traversed by Kind, Msg and Is.
+type RelatedErrors []error
+
+// Related returns RelatedErrors of errs. Nil errors are ignored.
+func Related(errs ...error) RelatedErrors {
+	return RelatedErrors(errs)
+}
+
+// WithRelated is like With but attaches related as RelatedErrors.
+// It returns nil if err is nil.
+func WithRelated(err error, related ...error) error {
+	if isNil(err) {
+		return nil
+	}
+	return With(err, Related(related...))
+}
+
+// RelatedOf returns the related errors attached in err's chain
+// from outermost to innermost.
+func RelatedOf(err error) []error {
+	var errs []error
+	walk(err, func(e *appError) bool {
+		errs = append(errs, e.related...)
+		return true
+	})
+	return errs
+}
+
+// IsIncludingRelated is like errors.Is but also matches target
+// against related errors in err's chain.
+func IsIncludingRelated(err, target error) bool {
+	if stderrors.Is(err, target) {
+		return true
+	}
+	for _, r := range RelatedOf(err) {
+		if IsIncludingRelated(r, target) {
+			return true
+		}
+	}
+	return false
+}
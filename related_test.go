@@ -0,0 +1,61 @@
+package errors_test
+
+import (
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestRelatedOf(t *testing.T) {
+	rollback := stderrors.New("rollback failed")
+	closeErr := stderrors.New("close failed")
+	tests := map[string]struct {
+		err  error
+		want []error
+	}{
+		"none":   {errors.E("app.Save", errors.KindConflict), nil},
+		"nil":    {errors.E("app.Save", errors.Related(nil, rollback, nil)), []error{rollback}},
+		"layers": {errors.E("app.Handle", errors.Related(closeErr), fmt.Errorf("save: %w", errors.E("app.Save", errors.Related(rollback)))), []error{closeErr, rollback}},
+		"with":   {errors.WithRelated(errors.E("app.Save", errors.Related(rollback)), closeErr), []error{rollback, closeErr}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.RelatedOf(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RelatedOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := errors.WithRelated(nil, rollback); got != nil {
+		t.Errorf("WithRelated(nil) = %v, want nil", got)
+	}
+}
+
+func TestIsIncludingRelated(t *testing.T) {
+	err := errors.E("app.Save", sql.ErrNoRows,
+		errors.Related(errors.E("app.Rollback", errors.Related(io.ErrUnexpectedEOF), sql.ErrTxDone)))
+	tests := map[string]struct {
+		target error
+		want   bool
+	}{
+		"chain":           {sql.ErrNoRows, true},
+		"related":         {sql.ErrTxDone, true},
+		"related related": {io.ErrUnexpectedEOF, true},
+		"other":           {io.EOF, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := errors.IsIncludingRelated(err, tt.target); got != tt.want {
+				t.Errorf("IsIncludingRelated() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+	if stderrors.Is(err, sql.ErrTxDone) {
+		t.Error("errors.Is() matched a related error")
+	}
+}
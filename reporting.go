@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+const reportedErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// ReportableStack renders the error's frames in the format of
+// runtime.Stack so that Cloud Error Reporting can group the errors.
+func ReportableStack(err error) string {
+	var b strings.Builder
+	b.WriteString("goroutine 1 [running]:\n")
+	for _, fr := range Frames(err) {
+		b.WriteString(fr.Function)
+		b.WriteString("(...)\n\t")
+		b.WriteString(fr.File)
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(fr.Line))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// LogPayload returns a structured log payload recognized by
+// Cloud Error Reporting. The service and the version set by
+// SetBuildInfo are reported as "serviceContext".
+func LogPayload(err error) map[string]interface{} {
+	msg := Msg(err)
+	payload := map[string]interface{}{
+		"@type":       reportedErrorEventType,
+		"message":     msg,
+		"severity":    Level(err),
+		"stack_trace": msg + "\n\n" + ReportableStack(err),
+	}
+	if svc, version, _ := buildOf(err); svc != "" || version != "" {
+		payload["serviceContext"] = map[string]interface{}{
+			"service": svc,
+			"version": version,
+		}
+	}
+	if e, ok := outermost(err); ok {
+		if fr, ok := e.location(); ok {
+			payload["context"] = map[string]interface{}{
+				"reportLocation": map[string]interface{}{
+					"filePath":     fr.File,
+					"lineNumber":   fr.Line,
+					"functionName": fr.Function,
+				},
+			}
+		}
+	}
+	return payload
+}
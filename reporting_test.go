@@ -0,0 +1,57 @@
+package errors_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestLogPayload(t *testing.T) {
+	e := errors.E("app.Get", errors.KindNotFound, "no user")
+	tests := map[string]error{
+		"E":       e,
+		"wrapped": fmt.Errorf("handle: %w", e),
+	}
+	for name, err := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := errors.LogPayload(err)
+			if got := p["@type"]; got != "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent" {
+				t.Errorf("@type = %v", got)
+			}
+			if got := p["message"]; got != errors.Msg(err) {
+				t.Errorf("message = %v, want %v", got, errors.Msg(err))
+			}
+			if got := p["severity"]; got != errors.Level(err) {
+				t.Errorf("severity = %v, want %v", got, errors.Level(err))
+			}
+			if got := p["stack_trace"]; got != errors.Msg(err)+"\n\n"+errors.ReportableStack(err) {
+				t.Errorf("stack_trace = %v, want the message and ReportableStack", got)
+			}
+			ctx, _ := p["context"].(map[string]interface{})
+			loc, _ := ctx["reportLocation"].(map[string]interface{})
+			if fn, _ := loc["functionName"].(string); !strings.HasSuffix(fn, ".TestLogPayload") {
+				t.Errorf("reportLocation = %v, want the location of E", loc)
+			}
+		})
+	}
+
+	if _, ok := errors.LogPayload(fmt.Errorf("plain"))["context"]; ok {
+		t.Error("LogPayload() has the context of a plain error")
+	}
+}
+
+func TestReportableStack(t *testing.T) {
+	err := errors.E("app.Handle", errors.E("app.Get", errors.KindNotFound))
+	got := errors.ReportableStack(err)
+	re := regexp.MustCompile(`^goroutine 1 \[running\]:\n` +
+		`go\.nownabe\.dev/errors_test\.TestReportableStack\(\.\.\.\)\n\t.*/reporting_test\.go:\d+\n`)
+	if !re.MatchString(got) {
+		t.Errorf("ReportableStack() =\n%s\nwant matching %s", got, re)
+	}
+	if got := errors.ReportableStack(fmt.Errorf("plain")); got != "goroutine 1 [running]:\n" {
+		t.Errorf("ReportableStack() = %q, want the header only", got)
+	}
+}
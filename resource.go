@@ -0,0 +1,37 @@
+package errors
+
+// ResourceRef identifies the resource an error is about.
+type ResourceRef struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+// Resource returns a ResourceRef which can be passed to E,
+// e.g. Resource("organization", "42").
+func Resource(kind, id string) ResourceRef {
+	return ResourceRef{Kind: kind, ID: id}
+}
+
+// ResourceOf returns the outermost resource set in err's chain.
+func ResourceOf(err error) (kind, id string, ok bool) {
+	walk(err, func(e *appError) bool {
+		if e.resource != nil {
+			kind, id, ok = e.resource.Kind, e.resource.ID, true
+		}
+		return !ok
+	})
+	return kind, id, ok
+}
+
+// ResourcesOf returns the resources set in err's chain
+// from outermost to innermost.
+func ResourcesOf(err error) []ResourceRef {
+	var refs []ResourceRef
+	walk(err, func(e *appError) bool {
+		if e.resource != nil {
+			refs = append(refs, *e.resource)
+		}
+		return true
+	})
+	return refs
+}
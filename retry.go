@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy configures RetryDecision and Retry.
+type RetryPolicy struct {
+	// Base is the wait after the first attempt, doubled per attempt.
+	// Zero means 100ms.
+	Base time.Duration
+	// Cap bounds the wait of the exponential backoff.
+	// Zero means an hour.
+	Cap time.Duration
+	// MaxAttempts stops retries after the number of attempts.
+	// Zero means no limit.
+	MaxAttempts int
+}
+
+// DefaultRetryPolicy is the policy used by RetryDecision.
+var DefaultRetryPolicy = RetryPolicy{
+	Base:        100 * time.Millisecond,
+	Cap:         30 * time.Second,
+	MaxAttempts: 5,
+}
+
+// RetryDecision is DefaultRetryPolicy.Decide.
+func RetryDecision(err error, attempt int) (retry bool, wait time.Duration) {
+	return DefaultRetryPolicy.Decide(err, attempt)
+}
+
+// Decide reports whether to retry after the attempt-th attempt,
+// counted from 1, failed with err and how long to wait.
+// Only transient errors are retried. The wait is the hint given by
+// RetryAfter if any, or an exponential backoff with jitter.
+func (p RetryPolicy) Decide(err error, attempt int) (retry bool, wait time.Duration) {
+	if err == nil || !IsTransient(err) {
+		return false, 0
+	}
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return false, 0
+	}
+	if d, ok := RetryAfter(err); ok {
+		return true, d
+	}
+	return true, p.backoff(attempt)
+}
+
+// The defaults of Base and Cap. Clamping to them keeps the backoff
+// from overflowing and from retrying without waiting.
+const (
+	defaultBackoffBase = 100 * time.Millisecond
+	defaultBackoffCap  = time.Hour
+)
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	base, limit := p.Base, p.Cap
+	if base <= 0 {
+		base = defaultBackoffBase
+	}
+	if limit <= 0 {
+		limit = defaultBackoffCap
+	}
+	d := base
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	if d > limit {
+		d = limit
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// Retry calls fn until it succeeds or policy decides not to retry.
+// The final error is wrapped with op and the number of attempts
+// as the field "attempts". It stops waiting when ctx is done.
+func Retry(ctx context.Context, policy RetryPolicy, op Op, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		retry, wait := policy.Decide(err, attempt)
+		if !retry {
+			return newError(1, op, []interface{}{err, Fields{"attempts": attempt}})
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return newError(1, op, []interface{}{err, Fields{"attempts": attempt}})
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package errors_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestDecide(t *testing.T) {
+	policy := errors.RetryPolicy{Base: 100 * time.Millisecond, Cap: time.Second, MaxAttempts: 10}
+	unavailable := errors.E("app.Get", errors.KindServiceUnavailable)
+	tests := map[string]struct {
+		err       error
+		attempt   int
+		wantRetry bool
+		min, max  time.Duration
+	}{
+		"nil":            {nil, 1, false, 0, 0},
+		"permanent":      {errors.E("app.Get", errors.KindNotFound), 1, false, 0, 0},
+		"first":          {unavailable, 1, true, 50 * time.Millisecond, 100 * time.Millisecond},
+		"third":          {unavailable, 3, true, 200 * time.Millisecond, 400 * time.Millisecond},
+		"capped":         {unavailable, 6, true, 500 * time.Millisecond, time.Second},
+		"max attempts":   {unavailable, 10, false, 0, 0},
+		"retry after":    {errors.E("app.Get", errors.KindTooManyRequests, 3*time.Second), 1, true, 3 * time.Second, 3 * time.Second},
+		"transient mark": {errors.E("app.Get", errors.Transient, "flaky"), 1, true, 50 * time.Millisecond, 100 * time.Millisecond},
+		"permanent mark": {errors.E("app.Get", errors.Permanent, unavailable), 1, false, 0, 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			retry, wait := policy.Decide(tt.err, tt.attempt)
+			if retry != tt.wantRetry {
+				t.Errorf("Decide() retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if wait < tt.min || wait > tt.max {
+				t.Errorf("Decide() wait = %v, want in [%v, %v]", wait, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestDecideClamped(t *testing.T) {
+	unavailable := errors.E("app.Get", errors.KindServiceUnavailable)
+	tests := map[string]struct {
+		policy   errors.RetryPolicy
+		attempt  int
+		min, max time.Duration
+	}{
+		"no cap":  {errors.RetryPolicy{Base: time.Second}, 1000, 30 * time.Minute, time.Hour},
+		"no base": {errors.RetryPolicy{}, 1, 50 * time.Millisecond, 100 * time.Millisecond},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			retry, wait := tt.policy.Decide(unavailable, tt.attempt)
+			if !retry || wait < tt.min || wait > tt.max {
+				t.Errorf("Decide() = %v, %v, want a wait in [%v, %v]", retry, wait, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryDecision(t *testing.T) {
+	unavailable := errors.E("app.Get", errors.KindServiceUnavailable)
+	if retry, _ := errors.RetryDecision(unavailable, 1); !retry {
+		t.Error("RetryDecision() = false for the first attempt, want true")
+	}
+	if retry, _ := errors.RetryDecision(unavailable, errors.DefaultRetryPolicy.MaxAttempts); retry {
+		t.Error("RetryDecision() = true for the last attempt, want false")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := errors.RetryPolicy{Base: time.Millisecond, Cap: time.Millisecond, MaxAttempts: 3}
+	unavailable := errors.E("app.Get", errors.KindServiceUnavailable)
+	tests := map[string]struct {
+		failures     int
+		err          error
+		wantErr      bool
+		wantAttempts int
+	}{
+		"succeeds":  {2, unavailable, false, 3},
+		"exhausted": {5, unavailable, true, 3},
+		"permanent": {5, errors.E("app.Get", errors.KindNotFound), true, 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			err := errors.Retry(context.Background(), policy, "app.Sync", func() error {
+				calls++
+				if calls <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Retry() = %v, want error %v", err, tt.wantErr)
+			}
+			if calls != tt.wantAttempts {
+				t.Errorf("fn called %d times, want %d", calls, tt.wantAttempts)
+			}
+			if err == nil {
+				return
+			}
+			if got := errors.FieldsOf(err)["attempts"]; got != tt.wantAttempts {
+				t.Errorf("attempts = %v, want %d", got, tt.wantAttempts)
+			}
+			if ops := errors.Ops(err); ops[0] != "app.Sync" {
+				t.Errorf("Ops() = %v, want app.Sync first", ops)
+			}
+			if !stderrors.Is(err, tt.err) {
+				t.Errorf("Retry() = %v, want it to wrap %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := errors.Retry(ctx, errors.RetryPolicy{Base: time.Hour}, "app.Sync", func() error {
+		calls++
+		cancel()
+		return errors.E("app.Get", errors.KindServiceUnavailable)
+	})
+
+	if err == nil || calls != 1 {
+		t.Errorf("Retry() = %v after %d calls, want the error after 1 call", err, calls)
+	}
+}
@@ -0,0 +1,33 @@
+package errors
+
+import stderrors "errors"
+
+// maxUnwrap bounds unwrapping so that self-referential chains terminate.
+const maxUnwrap = 1 << 10
+
+// Root returns the innermost error of err's chain.
+func Root(err error) error {
+	root, _ := Cause(err)
+	return root
+}
+
+// Cause returns the innermost error of err's chain
+// and the ops of the layers passed through on the way down.
+// Where the chain branches, the first branch is followed.
+func Cause(err error) (error, []Op) {
+	var ops []Op
+	for i := 0; err != nil && i < maxUnwrap; i++ {
+		if e, ok := err.(*appError); ok {
+			ops = append(ops, e.op)
+		}
+		next := stderrors.Unwrap(err)
+		if m, ok := err.(interface{ Unwrap() []error }); ok && len(m.Unwrap()) > 0 {
+			next = m.Unwrap()[0]
+		}
+		if isNil(next) {
+			break
+		}
+		err = next
+	}
+	return err, ops
+}
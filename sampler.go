@@ -0,0 +1,170 @@
+package errors
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.nownabe.dev/log"
+)
+
+// samplerCapacity bounds the number of fingerprints tracked by a Sampler.
+const samplerCapacity = 4096
+
+// Sampler limits how often errors with the same fingerprint are allowed
+// within a window. It is safe for concurrent use. The least recently
+// seen fingerprints are evicted when too many are tracked, and their
+// suppressed errors are kept until Flush.
+type Sampler struct {
+	window time.Duration
+	max    int
+
+	mu      sync.Mutex
+	order   *list.List
+	m       map[string]*list.Element
+	evicted []Suppressed
+	flushed time.Time
+}
+
+type sample struct {
+	fingerprint string
+	msg         string
+	level       log.Level
+	start       time.Time
+	count       int
+	suppressed  int
+}
+
+// Suppressed is the number of errors with a fingerprint
+// denied by a Sampler. Msg and Level are those of the first
+// error in the window.
+type Suppressed struct {
+	Fingerprint string
+	Msg         string
+	Level       log.Level
+	Count       int
+}
+
+// NewSampler returns a Sampler which allows at most max errors
+// with the same fingerprint per window.
+func NewSampler(window time.Duration, max int) *Sampler {
+	return &Sampler{
+		window: window,
+		max:    max,
+		order:  list.New(),
+		m:      map[string]*list.Element{},
+	}
+}
+
+// Allow reports whether err should be handled, e.g. logged.
+// When the first error of a new window is allowed, suppressed is
+// the number of errors denied in the previous window.
+func (s *Sampler) Allow(err error) (allowed bool, suppressed int) {
+	fp := Fingerprint(err)
+	t := now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.m[fp]
+	if !ok {
+		el = s.order.PushFront(&sample{fingerprint: fp, start: t})
+		s.m[fp] = el
+		if s.order.Len() > samplerCapacity {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			smp := oldest.Value.(*sample)
+			delete(s.m, smp.fingerprint)
+			if smp.suppressed > 0 && len(s.evicted) < samplerCapacity {
+				s.evicted = append(s.evicted, smp.report())
+			}
+		}
+	} else {
+		s.order.MoveToFront(el)
+	}
+
+	smp := el.Value.(*sample)
+	if t.Sub(smp.start) >= s.window {
+		suppressed = smp.suppressed
+		smp.start, smp.count, smp.suppressed = t, 0, 0
+	}
+	if smp.count == 0 {
+		smp.msg, smp.level = Msg(err), Level(err)
+	}
+	if smp.count >= s.max {
+		smp.suppressed++
+		return false, 0
+	}
+	smp.count++
+	return true, suppressed
+}
+
+// Flush returns the errors suppressed in the windows which have ended
+// without being reported by Allow, including those of the evicted
+// fingerprints, and resets their counts.
+func (s *Sampler) Flush() []Suppressed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush(now())
+}
+
+// flushDue is like Flush but returns nil
+// until a window has passed since the last flush.
+func (s *Sampler) flushDue() []Suppressed {
+	t := now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.Sub(s.flushed) < s.window {
+		return nil
+	}
+	return s.flush(t)
+}
+
+func (s *Sampler) flush(t time.Time) []Suppressed {
+	sups := s.evicted
+	s.evicted, s.flushed = nil, t
+	for el := s.order.Front(); el != nil; el = el.Next() {
+		smp := el.Value.(*sample)
+		if smp.suppressed > 0 && t.Sub(smp.start) >= s.window {
+			sups = append(sups, smp.report())
+			smp.suppressed = 0
+		}
+	}
+	return sups
+}
+
+func (smp *sample) report() Suppressed {
+	return Suppressed{Fingerprint: smp.fingerprint, Msg: smp.msg, Level: smp.level, Count: smp.suppressed}
+}
+
+var logSampler atomic.Pointer[Sampler]
+
+// SetSampler sets the Sampler consulted by Log and LogContext.
+// Denied errors are not logged, and the first error logged after
+// suppression reports the number of suppressed errors. The errors
+// suppressed but never reported so are logged by Log and LogContext
+// once a window with the number. Passing nil disables sampling.
+func SetSampler(s *Sampler) {
+	logSampler.Store(s)
+}
+
+// sampled returns the message to log, whether to log err
+// and the suppressed errors to log in addition.
+func sampled(err error) (string, bool, []Suppressed) {
+	msg := Msg(err)
+	s := logSampler.Load()
+	if s == nil {
+		return msg, true, nil
+	}
+	ok, n := s.Allow(err)
+	if n > 0 {
+		msg = suppressedMsg(msg, n)
+	}
+	return msg, ok, s.flushDue()
+}
+
+func suppressedMsg(msg string, n int) string {
+	return msg + " (suppressed " + strconv.Itoa(n) + " similar errors)"
+}
@@ -0,0 +1,169 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+// setClock fixes the clock and returns a function advancing it.
+func setClock(t *testing.T) func(d time.Duration) {
+	t.Helper()
+	var mu sync.Mutex
+	cur := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	errors.SetNow(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return cur
+	})
+	t.Cleanup(func() { errors.SetNow(nil) })
+	return func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		cur = cur.Add(d)
+	}
+}
+
+func TestSampler(t *testing.T) {
+	advance := setClock(t)
+	s := errors.NewSampler(time.Minute, 2)
+	err := errors.E("app.Get", errors.KindServiceUnavailable, errors.NoStack)
+
+	for i, want := range []bool{true, true, false, false, false} {
+		if got, n := s.Allow(err); got != want || n != 0 {
+			t.Errorf("Allow() #%d = %v, %d, want %v, 0", i, got, n, want)
+		}
+	}
+	if ok, _ := s.Allow(errors.E("app.Put", errors.NoStack)); !ok {
+		t.Error("Allow() denied another fingerprint")
+	}
+
+	advance(time.Minute)
+	if got, n := s.Allow(err); !got || n != 3 {
+		t.Errorf("Allow() = %v, %d, want true, 3 in the next window", got, n)
+	}
+	if got, n := s.Allow(err); !got || n != 0 {
+		t.Errorf("Allow() = %v, %d, want true, 0 after reporting", got, n)
+	}
+}
+
+func TestSamplerFlush(t *testing.T) {
+	advance := setClock(t)
+	s := errors.NewSampler(time.Minute, 1)
+	err := errors.E("app.Get", errors.KindServiceUnavailable, log.LevelWarn, "unavailable", errors.NoStack)
+	for range 4 {
+		s.Allow(err)
+	}
+
+	if got := s.Flush(); len(got) != 0 {
+		t.Errorf("Flush() = %v, want nothing in the window", got)
+	}
+	advance(time.Minute)
+	want := errors.Suppressed{Fingerprint: errors.Fingerprint(err), Msg: "unavailable", Level: log.LevelWarn, Count: 3}
+	if got := s.Flush(); len(got) != 1 || got[0] != want {
+		t.Errorf("Flush() = %v, want [%v]", got, want)
+	}
+	if got := s.Flush(); len(got) != 0 {
+		t.Errorf("Flush() = %v, want nothing after flushing", got)
+	}
+	if _, n := s.Allow(err); n != 0 {
+		t.Errorf("Allow() reported %d flushed errors, want 0", n)
+	}
+}
+
+func TestSamplerEvict(t *testing.T) {
+	setClock(t)
+	s := errors.NewSampler(time.Minute, 1)
+	first := stderrors.New("first")
+	s.Allow(first)
+	s.Allow(first)
+
+	for i := range 4096 {
+		s.Allow(stderrors.New(strconv.Itoa(i)))
+	}
+	got := s.Flush()
+	if len(got) != 1 || got[0].Fingerprint != errors.Fingerprint(first) || got[0].Count != 1 {
+		t.Fatalf("Flush() = %v, want the evicted fingerprint with 1 suppressed error", got)
+	}
+	if ok, n := s.Allow(first); !ok || n != 0 {
+		t.Errorf("Allow() = %v, %d, want the evicted fingerprint tracked again", ok, n)
+	}
+}
+
+func TestSamplerConcurrent(t *testing.T) {
+	advance := setClock(t)
+	s := errors.NewSampler(time.Minute, 10)
+	err := errors.E("app.Get", errors.KindServiceUnavailable, errors.NoStack)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if ok, _ := s.Allow(err); ok {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 10 {
+		t.Errorf("Allow() allowed %d errors, want 10", allowed)
+	}
+	advance(time.Minute)
+	if _, n := s.Allow(err); n != 790 {
+		t.Errorf("Allow() reported %d suppressed errors, want 790", n)
+	}
+}
+
+type recordLogger struct {
+	msgs []string
+}
+
+func (l *recordLogger) Log(level log.Level, msg string, keysAndValues ...interface{}) {
+	l.msgs = append(l.msgs, msg)
+}
+
+func TestLogSampled(t *testing.T) {
+	advance := setClock(t)
+	errors.SetSampler(errors.NewSampler(time.Minute, 1))
+	defer errors.SetSampler(nil)
+
+	var l recordLogger
+	get := errors.E("app.Get", errors.KindServiceUnavailable, "get user", errors.NoStack)
+	put := errors.E("app.Put", errors.KindServiceUnavailable, "put user", errors.NoStack)
+	for range 3 {
+		errors.Log(&l, get)
+		errors.Log(&l, put)
+	}
+	advance(time.Minute)
+	errors.Log(&l, get)
+
+	want := []string{
+		"get user",
+		"put user",
+		"put user (suppressed 2 similar errors)",
+		"get user (suppressed 2 similar errors)",
+	}
+	if len(l.msgs) != len(want) {
+		t.Fatalf("logged %q, want %q", l.msgs, want)
+	}
+	for i := range want {
+		if l.msgs[i] != want[i] {
+			t.Errorf("logged #%d %q, want %q", i, l.msgs[i], want[i])
+		}
+	}
+}
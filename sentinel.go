@@ -0,0 +1,43 @@
+package errors
+
+// kindSentinel is a sentinel error standing for a kind.
+type kindSentinel struct {
+	kind KindCode
+}
+
+func (s *kindSentinel) Error() string {
+	return s.kind.String()
+}
+
+// Sentinels of kinds to be used with the standard errors.Is,
+// e.g. errors.Is(err, ErrNotFound).
+//
+// Unlike Kind, a sentinel matches the kind of any layer in the chain,
+// including kinds masked by outer layers: errors.Is reports true for
+// ErrNotFound when a KindBadRequest layer wraps a KindNotFound one,
+// since the standard errors.Is consults every layer and a layer cannot
+// see the layers wrapping it. Use Is(err, kind) of this package
+// to match the effective kind only.
+var (
+	ErrBadRequest          error = &kindSentinel{KindBadRequest}
+	ErrUnauthorized        error = &kindSentinel{KindUnauthorized}
+	ErrForbidden           error = &kindSentinel{KindForbidden}
+	ErrNotFound            error = &kindSentinel{KindNotFound}
+	ErrConflict            error = &kindSentinel{KindConflict}
+	ErrGone                error = &kindSentinel{KindGone}
+	ErrPreconditionFailed  error = &kindSentinel{KindPreconditionFailed}
+	ErrUnprocessableEntity error = &kindSentinel{KindUnprocessableEntity}
+	ErrTooManyRequests     error = &kindSentinel{KindTooManyRequests}
+	ErrUnexpected          error = &kindSentinel{KindUnexpected}
+	ErrNotImplemented      error = &kindSentinel{KindNotImplemented}
+	ErrServiceUnavailable  error = &kindSentinel{KindServiceUnavailable}
+	ErrGatewayTimeout      error = &kindSentinel{KindGatewayTimeout}
+)
+
+// Is reports whether target is the sentinel of the kind returned
+// by Kind for this layer. Since errors.Is consults every layer,
+// kinds of inner layers also match, even when masked.
+func (err *appError) Is(target error) bool {
+	s, ok := target.(*kindSentinel)
+	return ok && Kind(err) == s.kind
+}
@@ -0,0 +1,49 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestSentinel(t *testing.T) {
+	notFound := errors.E("store.Get", errors.KindNotFound)
+	tests := map[string]struct {
+		err    error
+		target error
+		want   bool
+	}{
+		"leaf":              {notFound, errors.ErrNotFound, true},
+		"other kind":        {notFound, errors.ErrConflict, false},
+		"inherited":         {errors.E("api.Get", notFound), errors.ErrNotFound, true},
+		"fmt between":       {errors.E("api.Get", fmt.Errorf("get: %w", notFound)), errors.ErrNotFound, true},
+		"fmt outside":       {fmt.Errorf("handle: %w", errors.E("api.Get", notFound)), errors.ErrNotFound, true},
+		"fmt everywhere":    {fmt.Errorf("a: %w", errors.E("api.Get", fmt.Errorf("b: %w", notFound))), errors.ErrNotFound, true},
+		"outer kind":        {errors.E("api.Get", errors.KindBadRequest, fmt.Errorf("get: %w", notFound)), errors.ErrBadRequest, true},
+		"masked inner kind": {errors.E("api.Get", errors.KindBadRequest, fmt.Errorf("get: %w", notFound)), errors.ErrNotFound, true},
+		"plain":             {stderrors.New("failed"), errors.ErrUnexpected, false},
+		"kindless":          {fmt.Errorf("get: %w", errors.E("api.Get", "failed")), errors.ErrUnexpected, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := stderrors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentinelMaskedKind(t *testing.T) {
+	err := errors.E("api.Get", errors.KindBadRequest, fmt.Errorf("get: %w", errors.E("store.Get", errors.KindNotFound)))
+
+	// The standard errors.Is reaches the masked inner kind,
+	// while Is matches the effective kind only.
+	if !stderrors.Is(err, errors.ErrNotFound) {
+		t.Error("errors.Is(ErrNotFound) = false, want true for the inner layer")
+	}
+	if errors.Is(err, errors.KindNotFound) {
+		t.Error("Is(KindNotFound) = true, want false for the masked kind")
+	}
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// SlogValue returns the error as a slog group value.
+// The chain is flattened into aggregated attributes.
+func SlogValue(err error) slog.Value {
+	if err == nil {
+		return slog.Value{}
+	}
+	if _, ok := err.(*appError); !ok {
+		return slog.GroupValue(slog.String("msg", err.Error()))
+	}
+
+	frames := Frames(err)
+	stack := make([]slog.Attr, len(frames))
+	for i, fr := range frames {
+		stack[i] = slog.String(strconv.Itoa(i), fr.String())
+	}
+
+	attrs := []slog.Attr{
+		slog.String("msg", Msg(err)),
+		slog.Int("kind", int(Kind(err))),
+		slog.String("kind_text", KindText(err)),
+		slog.Any("ops", Ops(err)),
+		slog.Any("level", Level(err)),
+	}
+	if user := userOf(err); user != "" {
+		attrs = append(attrs, slog.String("user", user))
+	}
+	svc, version, host := buildOf(err)
+	for _, a := range [...]slog.Attr{slog.String("service", svc), slog.String("version", version), slog.String("host", host)} {
+		if a.Value.String() != "" {
+			attrs = append(attrs, a)
+		}
+	}
+	attrs = append(attrs, slog.Attr{Key: "stack", Value: slog.GroupValue(stack...)})
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer.
+func (err *appError) LogValue() slog.Value {
+	return SlogValue(err)
+}
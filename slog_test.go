@@ -0,0 +1,29 @@
+package errors_test
+
+import (
+	"log/slog"
+	"os"
+
+	"go.nownabe.dev/errors"
+)
+
+func ExampleSlogValue() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// Drop the values which depend on the environment.
+			switch {
+			case len(groups) == 0 && a.Key == slog.TimeKey:
+				return slog.Attr{}
+			case len(groups) == 1 && (a.Key == "version" || a.Key == "host"):
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	err := errors.E("app.Handle", errors.NoStack, "handle request",
+		errors.E("app.Get", errors.NoStack, errors.KindNotFound, "no user"))
+	logger.Error("failed", "error", err)
+	// Output:
+	// {"level":"ERROR","msg":"failed","error":{"msg":"handle request: no user","kind":404,"kind_text":"Not Found","ops":["app.Handle","app.Get"],"level":500}}
+}
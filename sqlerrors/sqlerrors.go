@@ -0,0 +1,68 @@
+// Package sqlerrors classifies database errors into kinds
+// of go.nownabe.dev/errors.
+package sqlerrors // import "go.nownabe.dev/errors/sqlerrors"
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+
+	"go.nownabe.dev/errors"
+)
+
+// SQLStateKey is the field key of SQLSTATE codes.
+const SQLStateKey = "sqlstate"
+
+// SQLSTATE codes classified by Classify.
+const (
+	UniqueViolation      = "23505"
+	ForeignKeyViolation  = "23503"
+	SerializationFailure = "40001"
+)
+
+// Classify wraps err with op and assigns a kind:
+//
+//	sql.ErrNoRows             KindNotFound
+//	sql.ErrTxDone             KindUnexpected
+//	unique violation          KindConflict
+//	foreign key violation     KindBadRequest
+//	serialization failure     KindConflict, Transient
+//	context.DeadlineExceeded  KindGatewayTimeout
+//	context.Canceled          KindClientClosedRequest
+//
+// SQLSTATE codes are read from errors implementing SQLState() string,
+// such as those of pgx, and attached as the field "sqlstate".
+// Other errors are wrapped without kinds. It returns nil if err is nil.
+func Classify(op errors.Op, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	args := []interface{}{err}
+	var st interface{ SQLState() string }
+	if stderrors.As(err, &st) {
+		code := st.SQLState()
+		args = append(args, errors.Fields{SQLStateKey: code})
+		switch code {
+		case UniqueViolation:
+			args = append(args, errors.KindConflict)
+		case ForeignKeyViolation:
+			args = append(args, errors.KindBadRequest)
+		case SerializationFailure:
+			args = append(args, errors.KindConflict, errors.Transient)
+		}
+	}
+
+	switch {
+	case stderrors.Is(err, sql.ErrNoRows):
+		args = append(args, errors.KindNotFound)
+	case stderrors.Is(err, sql.ErrTxDone):
+		args = append(args, errors.KindUnexpected)
+	case stderrors.Is(err, context.DeadlineExceeded):
+		args = append(args, errors.KindGatewayTimeout)
+	case stderrors.Is(err, context.Canceled):
+		args = append(args, errors.KindClientClosedRequest)
+	}
+
+	return errors.ECaller(1, op, args...)
+}
@@ -0,0 +1,64 @@
+package sqlerrors_test
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/errors/sqlerrors"
+)
+
+// pgError is an error of a driver reporting SQLSTATE codes like pgx.
+type pgError struct {
+	code string
+}
+
+func (err *pgError) Error() string    { return "pg error " + err.code }
+func (err *pgError) SQLState() string { return err.code }
+
+func TestClassify(t *testing.T) {
+	tests := map[string]struct {
+		err       error
+		kind      errors.KindCode
+		sqlstate  string
+		transient bool
+	}{
+		"no rows":               {sql.ErrNoRows, errors.KindNotFound, "", false},
+		"wrapped no rows":       {fmt.Errorf("scan: %w", sql.ErrNoRows), errors.KindNotFound, "", false},
+		"tx done":               {sql.ErrTxDone, errors.KindUnexpected, "", false},
+		"unique violation":      {&pgError{sqlerrors.UniqueViolation}, errors.KindConflict, "23505", false},
+		"foreign key violation": {&pgError{sqlerrors.ForeignKeyViolation}, errors.KindBadRequest, "23503", false},
+		"serialization failure": {&pgError{sqlerrors.SerializationFailure}, errors.KindConflict, "40001", true},
+		"other sqlstate":        {&pgError{"42P01"}, errors.KindUnexpected, "42P01", false},
+		"deadline exceeded":     {context.DeadlineExceeded, errors.KindGatewayTimeout, "", true},
+		"canceled":              {context.Canceled, errors.KindClientClosedRequest, "", false},
+		"other":                 {stderrors.New("bad connection"), errors.KindUnexpected, "", false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := sqlerrors.Classify("db.GetUser", tt.err)
+			if got := errors.Kind(err); got != tt.kind {
+				t.Errorf("Kind() = %d, want %d", got, tt.kind)
+			}
+			if got, _ := errors.FieldsOf(err)[sqlerrors.SQLStateKey].(string); got != tt.sqlstate {
+				t.Errorf("sqlstate = %q, want %q", got, tt.sqlstate)
+			}
+			if got := errors.IsTransient(err); got != tt.transient {
+				t.Errorf("IsTransient() = %t, want %t", got, tt.transient)
+			}
+			if !stderrors.Is(err, tt.err) {
+				t.Error("Classify() does not wrap the error")
+			}
+			if ops := errors.Ops(err); len(ops) == 0 || ops[0] != "db.GetUser" {
+				t.Errorf("Ops() = %v, want db.GetUser first", ops)
+			}
+		})
+	}
+
+	if err := sqlerrors.Classify("db.GetUser", nil); err != nil {
+		t.Errorf("Classify(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,268 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.nownabe.dev/log"
+)
+
+const pkgPrefix = "go.nownabe.dev/errors."
+
+var depth int32 = 32
+
+var (
+	filtersMu    sync.RWMutex
+	stackFilters = []string{pkgPrefix}
+)
+
+// AddStackFilter registers a function name prefix such as
+// "example.com/app/internal/fail." whose frames are skipped
+// when resolving locations and stacktraces.
+// Frames of this package are always skipped.
+func AddStackFilter(prefix string) {
+	filtersMu.Lock()
+	defer filtersMu.Unlock()
+	stackFilters = append(stackFilters, prefix)
+}
+
+func filtered(function string) bool {
+	filtersMu.RLock()
+	defer filtersMu.RUnlock()
+	for _, p := range stackFilters {
+		if strings.HasPrefix(function, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// StackMode controls when E captures stacks.
+type StackMode int32
+
+const (
+	// StackAlways captures stacks for every error.
+	StackAlways StackMode = iota + 1
+	// StackNever never captures stacks.
+	StackNever
+	// StackErrorLevelOnly captures stacks only for errors
+	// whose level is error or more severe.
+	StackErrorLevelOnly
+
+	// NoStack can be passed to E to skip capturing the stack.
+	NoStack = StackNever
+)
+
+var stackMode = int32(StackAlways)
+
+// SetStackCapture sets when E captures stacks.
+// Errors without stacks have empty frames.
+func SetStackCapture(mode StackMode) {
+	atomic.StoreInt32(&stackMode, int32(mode))
+}
+
+func (err *appError) captures() bool {
+	mode := err.stack
+	if mode == 0 {
+		mode = StackMode(atomic.LoadInt32(&stackMode))
+	}
+	switch mode {
+	case StackNever:
+		return false
+	case StackErrorLevelOnly:
+		return err.level == 0 || err.level >= log.LevelError
+	}
+	return true
+}
+
+// SetStackDepth sets the maximum number of frames captured by E.
+func SetStackDepth(n int) {
+	if n < 1 {
+		n = 1
+	}
+	atomic.StoreInt32(&depth, int32(n))
+}
+
+func stackDepth() int {
+	return int(atomic.LoadInt32(&depth))
+}
+
+// Frame is a resolved stack frame.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// String returns the frame as "file:line (function)".
+func (f Frame) String() string {
+	return f.File + ":" + strconv.Itoa(f.Line) + " (" + f.Function + ")"
+}
+
+// callers resolves the captured frames, skipping filtered frames.
+func (err *appError) callers() []Frame {
+	if err.decodedFrames != nil {
+		return err.decodedFrames
+	}
+	if len(err.frames) == 0 {
+		return nil
+	}
+	var frs []Frame
+	frames := runtime.CallersFrames(err.frames)
+	for {
+		fr, more := frames.Next()
+		if fr.Function != "" && !filtered(fr.Function) {
+			frs = append(frs, Frame{Function: fr.Function, File: fr.File, Line: fr.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return frs
+}
+
+func (err *appError) location() (Frame, bool) {
+	frs := err.callers()
+	if len(frs) == 0 {
+		return Frame{}, false
+	}
+	return frs[0], true
+}
+
+// Frames returns the frames of the outermost error followed by
+// the creation frames of wrapped errors, including the frames recorded
+// by errors created elsewhere with xerrors.Errorf.
+// Frames shared with the previous layer and consecutive identical frames
+// are omitted.
+func Frames(err error) []Frame {
+	frames := []Frame{}
+	var prev map[Frame]bool
+	visit(err, func(err error) bool {
+		e, ok := err.(*appError)
+		if !ok {
+			if fr, ok := foreignFrame(err); ok && (len(frames) == 0 || frames[len(frames)-1] != fr) {
+				frames = append(frames, fr)
+			}
+			return true
+		}
+		frs := e.callers()
+		if prev != nil && len(frs) > 1 {
+			frs = frs[:1]
+		}
+		cur := make(map[Frame]bool, len(frs))
+		for _, fr := range frs {
+			cur[fr] = true
+			if !prev[fr] && (len(frames) == 0 || frames[len(frames)-1] != fr) {
+				frames = append(frames, fr)
+			}
+		}
+		prev = cur
+		return true
+	})
+	return frames
+}
+
+// Stacktrace returns an array of stacktrace tupples
+// that inclues function, file and line.
+// It is the same as Frames in the tuple form.
+func Stacktrace(err error) [][3]string {
+	frames := [][3]string{}
+	for _, fr := range Frames(err) {
+		frames = append(frames, [3]string{fr.Function, fr.File, strconv.Itoa(fr.Line)})
+	}
+	return frames
+}
+
+// StackTrace is a stack of frames as captured by runtime.Callers.
+// It is what the StackTrace method returns, which tools such as
+// sentry-go look for in place of capturing the reporting site.
+// It formats like the StackTrace of github.com/pkg/errors.
+type StackTrace []StackFrame
+
+// Format formats the stack of frames. %+v prints each frame
+// on a new line, and %s and %v print them in brackets.
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		for _, f := range st {
+			io.WriteString(s, "\n")
+			f.Format(s, verb)
+		}
+	case verb == 'v' && s.Flag('#'):
+		fmt.Fprintf(s, "%#v", []StackFrame(st))
+	case verb == 's' || verb == 'v':
+		io.WriteString(s, "[")
+		for i, f := range st {
+			if i > 0 {
+				io.WriteString(s, " ")
+			}
+			f.Format(s, verb)
+		}
+		io.WriteString(s, "]")
+	}
+}
+
+// StackFrame is a program counter of a StackTrace.
+// It formats like the Frame of github.com/pkg/errors.
+type StackFrame uintptr
+
+func (f StackFrame) frame() Frame {
+	fr, _ := runtime.CallersFrames([]uintptr{uintptr(f)}).Next()
+	if fr.Function == "" {
+		return Frame{Function: "unknown", File: "unknown"}
+	}
+	return Frame{Function: fr.Function, File: fr.File, Line: fr.Line}
+}
+
+// Format formats the frame. %s prints the base name of the file,
+// %d the line, %n the function name and %v the file and the line
+// like "errors.go:42". The + flag prints the function name and
+// the full path of the file with %s and %v.
+func (f StackFrame) Format(s fmt.State, verb rune) {
+	fr := f.frame()
+	switch verb {
+	case 's':
+		if s.Flag('+') {
+			io.WriteString(s, fr.Function+"\n\t"+fr.File)
+			return
+		}
+		io.WriteString(s, path.Base(fr.File))
+	case 'd':
+		io.WriteString(s, strconv.Itoa(fr.Line))
+	case 'n':
+		name := fr.Function[strings.LastIndex(fr.Function, "/")+1:]
+		io.WriteString(s, name[strings.Index(name, ".")+1:])
+	case 'v':
+		f.Format(s, 's')
+		io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// StackPCs returns the program counters captured
+// by the outermost layer constructed by E.
+func StackPCs(err error) []uintptr {
+	e, ok := outermost(err)
+	if !ok || len(e.frames) == 0 {
+		return nil
+	}
+	return append([]uintptr(nil), e.frames...)
+}
+
+// StackTrace returns the frames captured by this layer,
+// skipping filtered frames as Frames does.
+func (err *appError) StackTrace() StackTrace {
+	var st StackTrace
+	for _, pc := range StackPCs(err) {
+		if f := StackFrame(pc); !filtered(f.frame().Function) {
+			st = append(st, f)
+		}
+	}
+	return st
+}
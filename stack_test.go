@@ -0,0 +1,139 @@
+package errors_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestStackCapture(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  errors.StackMode
+		args  []interface{}
+		stack bool
+	}{
+		{"always", errors.StackAlways, nil, true},
+		{"never", errors.StackNever, nil, false},
+		{"error level only with info", errors.StackErrorLevelOnly, []interface{}{log.LevelInfo}, false},
+		{"error level only with error", errors.StackErrorLevelOnly, []interface{}{log.LevelError}, true},
+		{"error level only without level", errors.StackErrorLevelOnly, nil, true},
+		{"NoStack", errors.StackAlways, []interface{}{errors.NoStack}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors.SetStackCapture(tt.mode)
+			defer errors.SetStackCapture(errors.StackAlways)
+
+			err := errors.E("app.Get", append(tt.args, "no user")...)
+			if got := len(errors.Stacktrace(err)) > 0; got != tt.stack {
+				t.Errorf("has stack = %t, want %t", got, tt.stack)
+			}
+			if s := fmt.Sprintf("%+v", err); s == "" {
+				t.Error("detailed format is empty")
+			}
+		})
+	}
+}
+
+func fail(op errors.Op) error {
+	return errors.ECaller(1, op, "failed")
+}
+
+func TestLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		fn   string
+	}{
+		{"E", errors.E("app.Get", "no user"), "TestLocation"},
+		{"Wrap", errors.Wrap("app.Get", fmt.Errorf("plain")), "TestLocation"},
+		{"ECaller", fail("app.Get"), "TestLocation"},
+		{"ECaller(0)", errors.ECaller(0, "app.Get"), "TestLocation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := errors.Frames(tt.err)
+			if len(frames) == 0 {
+				t.Fatal("no frames")
+			}
+			fr := frames[0]
+			if filepath.Base(fr.File) != "stack_test.go" {
+				t.Errorf("file = %s, want stack_test.go", fr.File)
+			}
+			if !strings.HasSuffix(fr.Function, "."+tt.fn) {
+				t.Errorf("function = %s, want %s", fr.Function, tt.fn)
+			}
+		})
+	}
+}
+
+func createUser() error {
+	return errors.E("app.CreateUser", errors.KindConflict)
+}
+
+func ExampleStackPCs() {
+	st := sentry.ExtractStacktrace(createUser())
+	fr := st.Frames[len(st.Frames)-1]
+	fmt.Println(fr.Module, fr.Function)
+	// Output: go.nownabe.dev/errors_test createUser
+}
+
+//go:noinline
+func newFiltered() error {
+	return errors.E("app.Get", errors.KindNotFound)
+}
+
+func TestAddStackFilter(t *testing.T) {
+	errors.AddStackFilter("go.nownabe.dev/errors_test.newFiltered")
+	err := newFiltered()
+
+	if fr := errors.Frames(err)[0]; !strings.HasSuffix(fr.Function, ".TestAddStackFilter") {
+		t.Errorf("Frames()[0] = %s, want TestAddStackFilter", fr.Function)
+	}
+	st := err.(interface{ StackTrace() errors.StackTrace }).StackTrace()
+	if got := fmt.Sprintf("%n", st[0]); got != "TestAddStackFilter" {
+		t.Errorf("StackTrace()[0] = %s, want TestAddStackFilter", got)
+	}
+	for _, f := range st {
+		if got := fmt.Sprintf("%+s", f); strings.HasPrefix(got, "go.nownabe.dev/errors.") {
+			t.Errorf("StackTrace() has the frame of %s", got)
+		}
+	}
+}
+
+func TestStackTraceFormat(t *testing.T) {
+	err := errors.E("app.Get", errors.KindNotFound)
+	_, _, line, _ := runtime.Caller(0)
+	st := err.(interface{ StackTrace() errors.StackTrace }).StackTrace()
+	if len(st) == 0 {
+		t.Fatal("StackTrace() is empty")
+	}
+
+	fr := st[0]
+	tests := map[string]string{
+		"%s":  "stack_test.go",
+		"%d":  strconv.Itoa(line - 1),
+		"%n":  "TestStackTraceFormat",
+		"%v":  "stack_test.go:" + strconv.Itoa(line-1),
+		"%+s": "go.nownabe.dev/errors_test.TestStackTraceFormat\n\t",
+	}
+	for format, want := range tests {
+		if got := fmt.Sprintf(format, fr); !strings.HasPrefix(got, want) {
+			t.Errorf("Sprintf(%q) = %q, want %q", format, got, want)
+		}
+	}
+	if got := fmt.Sprintf("%+v", st); !strings.HasPrefix(got, "\ngo.nownabe.dev/errors_test.TestStackTraceFormat\n\t") {
+		t.Errorf("Sprintf(%%+v) = %q, want a frame per line", got)
+	}
+	if got := fmt.Sprintf("%v", st); !strings.HasPrefix(got, "[stack_test.go:") || !strings.HasSuffix(got, "]") {
+		t.Errorf("Sprintf(%%v) = %q, want the frames in brackets", got)
+	}
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var stats = struct {
+	sync.RWMutex
+	kinds map[KindCode]*atomic.Uint64
+	ops   map[Op]*atomic.Uint64
+}{
+	kinds: map[KindCode]*atomic.Uint64{},
+	ops:   map[Op]*atomic.Uint64{},
+}
+
+// maxStatsOps bounds the ops counted separately by Stats,
+// since ops such as paths of requests may be unbounded.
+const maxStatsOps = 1024
+
+// otherOp is the op counting the ops beyond maxStatsOps.
+// It never clashes with ops which pass Op.Validate.
+const otherOp Op = "other"
+
+// count increments the counter of key in m. Once m holds limit keys,
+// new keys are counted as other. A limit of 0 means no limit.
+func count[K comparable](m map[K]*atomic.Uint64, key K, limit int, other K) {
+	stats.RLock()
+	c, ok := m[key]
+	stats.RUnlock()
+	if !ok {
+		stats.Lock()
+		if c, ok = m[key]; !ok {
+			if limit > 0 && len(m) >= limit {
+				key = other
+			}
+			if c, ok = m[key]; !ok {
+				c = new(atomic.Uint64)
+				m[key] = c
+			}
+		}
+		stats.Unlock()
+	}
+	c.Add(1)
+}
+
+func record(e *appError) {
+	count(stats.kinds, Kind(e), 0, 0)
+	count(stats.ops, e.op, maxStatsOps, otherOp)
+}
+
+// Stats returns the numbers of errors constructed so far
+// keyed by "kind:404" for kinds and "op:pkg.Func" for ops.
+// Ops beyond the first 1024 are counted together as "op:other".
+func Stats() map[string]uint64 {
+	stats.RLock()
+	defer stats.RUnlock()
+	m := make(map[string]uint64, len(stats.kinds)+len(stats.ops))
+	for k, c := range stats.kinds {
+		m["kind:"+strconv.Itoa(int(k))] = c.Load()
+	}
+	for op, c := range stats.ops {
+		m["op:"+string(op)] = c.Load()
+	}
+	return m
+}
+
+// ResetStats resets the counters of Stats.
+func ResetStats() {
+	stats.Lock()
+	defer stats.Unlock()
+	clear(stats.kinds)
+	clear(stats.ops)
+}
+
+var publishOnce sync.Once
+
+// PublishExpvar publishes Stats as the expvar "go.nownabe.dev/errors".
+// It is safe to call more than once.
+func PublishExpvar() {
+	publishOnce.Do(func() {
+		expvar.Publish("go.nownabe.dev/errors", expvar.Func(func() interface{} {
+			return Stats()
+		}))
+	})
+}
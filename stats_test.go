@@ -0,0 +1,61 @@
+package errors_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestStats(t *testing.T) {
+	errors.ResetStats()
+	defer errors.ResetStats()
+
+	_ = errors.E("app.Get", errors.KindNotFound)
+	_ = errors.E("app.Get", errors.KindNotFound)
+	_ = errors.E("app.List", errors.KindBadRequest)
+
+	want := map[string]uint64{
+		"kind:404":    2,
+		"kind:400":    1,
+		"op:app.Get":  2,
+		"op:app.List": 1,
+	}
+	got := errors.Stats()
+	for k, w := range want {
+		if got[k] != w {
+			t.Errorf("Stats()[%q] = %d, want %d", k, got[k], w)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Stats() = %v, want %v", got, want)
+	}
+}
+
+func TestStatsBounded(t *testing.T) {
+	errors.ResetStats()
+	defer errors.ResetStats()
+
+	for i := 0; i < 1500; i++ {
+		_ = errors.E(errors.Op("GET /users/"+strconv.Itoa(i)), errors.KindNotFound)
+	}
+	_ = errors.E("GET /users/0", errors.KindNotFound)
+
+	ops := 0
+	for k := range errors.Stats() {
+		if strings.HasPrefix(k, "op:") {
+			ops++
+		}
+	}
+	if ops != 1025 {
+		t.Errorf("Stats() has %d ops, want 1024 and the other bucket", ops)
+	}
+	s := errors.Stats()
+	if got := s["op:other"]; got != 1500-1024 {
+		t.Errorf(`Stats()["op:other"] = %d, want %d`, got, 1500-1024)
+	}
+	if got := s["op:GET /users/0"]; got != 2 {
+		t.Errorf(`Stats()["op:GET /users/0"] = %d, want 2`, got)
+	}
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"go.nownabe.dev/log"
+)
+
+// ErrorSummary is a compact summary of an error for dashboards.
+type ErrorSummary struct {
+	Kind        KindCode  `json:"kind"`
+	KindText    string    `json:"kind_text"`
+	Code        Code      `json:"code,omitempty"`
+	TopOp       Op        `json:"top_op,omitempty"`
+	RootCause   string    `json:"root_cause"`
+	Msg         string    `json:"msg"`
+	Depth       int       `json:"depth"`
+	Level       log.Level `json:"level"`
+	Fingerprint string    `json:"fingerprint"`
+	Service     string    `json:"service,omitempty"`
+	Version     string    `json:"version,omitempty"`
+	Host        string    `json:"host,omitempty"`
+}
+
+// Summary returns the summary of err. The kind, the level, the code,
+// the outermost op, the root cause and the depth are collected
+// in a single traversal, while the message and the fingerprint are
+// those of Msg and Fingerprint. It returns the zero value for nil.
+func Summary(err error) ErrorSummary {
+	if err == nil {
+		return ErrorSummary{}
+	}
+
+	var (
+		s    ErrorSummary
+		root error
+		p    precedence
+	)
+	n, limit := 0, depthLimit()
+	visitN(err, func(err error) bool {
+		s.Depth++
+		if s.Depth > limit {
+			return true
+		}
+		if root == nil && isLeaf(err) {
+			root = err
+		}
+		e, ok := err.(*appError)
+		if !ok {
+			return true
+		}
+		p.add(e)
+		if s.Code == "" {
+			s.Code = e.code
+		}
+		if s.TopOp == "" && !e.derivedOp {
+			s.TopOp = e.op
+		}
+		return true
+	}, &n, maxUnwrap)
+
+	s.Kind, s.Level = p.result(err)
+	s.KindText = s.Kind.String()
+	if root != nil {
+		s.RootCause = redact(root.Error())
+	}
+	s.Msg = Msg(err)
+	s.Fingerprint = fingerprint(err, s.Kind, false)
+	s.Service, s.Version, s.Host = buildOf(err)
+	return s
+}
+
+// Depth returns the number of errors in err's chain
+// including errors not constructed by E. It counts up to 1024
+// errors so that self-referential chains terminate.
+func Depth(err error) int {
+	return chainLen(err)
+}
+
+// isLeaf reports whether err wraps no errors.
+func isLeaf(err error) bool {
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		return len(u.Unwrap()) == 0
+	case interface{ Unwrap() error }:
+		return isNil(u.Unwrap())
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestSummary(t *testing.T) {
+	root := stderrors.New("sql: no rows in result set")
+	tests := map[string]struct {
+		err  error
+		want errors.ErrorSummary
+	}{
+		"nil": {
+			err:  nil,
+			want: errors.ErrorSummary{},
+		},
+		"plain": {
+			err: fmt.Errorf("get: %w", root),
+			want: errors.ErrorSummary{
+				Kind:      errors.KindUnexpected,
+				KindText:  "Internal Server Error",
+				RootCause: "sql: no rows in result set",
+				Msg:       "get: sql: no rows in result set",
+				Depth:     2,
+				Level:     log.LevelError,
+			},
+		},
+		"chain": {
+			err: errors.E("app.Handle", errors.NoStack,
+				errors.E("app.Get", errors.NoStack, errors.KindNotFound, log.LevelWarn, "no user", root)),
+			want: errors.ErrorSummary{
+				Kind:      errors.KindNotFound,
+				KindText:  "Not Found",
+				TopOp:     "app.Handle",
+				RootCause: "sql: no rows in result set",
+				Msg:       "no user",
+				Depth:     3,
+				Level:     log.LevelWarn,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := errors.Summary(tt.err)
+			if tt.err != nil {
+				if got.Fingerprint != errors.Fingerprint(tt.err) {
+					t.Errorf("Summary().Fingerprint = %q, want %q", got.Fingerprint, errors.Fingerprint(tt.err))
+				}
+				tt.want.Fingerprint = got.Fingerprint
+			}
+			tt.want.Service, tt.want.Version, tt.want.Host = got.Service, got.Version, got.Host
+			if got != tt.want {
+				t.Errorf("Summary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package errors
+
+// Template is a well-known error defined by Define.
+type Template struct {
+	kind KindCode
+	msg  string
+}
+
+// Define defines a template of errors with kind and msg.
+func Define(kind KindCode, msg string) *Template {
+	return &Template{kind: kind, msg: msg}
+}
+
+// New constructs an error from the template like E.
+// The kind and the message of the template can be overridden by args.
+func (t *Template) New(op Op, args ...interface{}) error {
+	e := newAppError(op)
+	e.template = t
+	errs, wrapped := e.set(append([]interface{}{t.kind, t.msg}, args...))
+	e.finish(1, errs, wrapped)
+	notify(e)
+	return e
+}
+
+// IsTemplate reports whether any error in err's chain
+// was constructed from t.
+func IsTemplate(err error, t *Template) bool {
+	found := false
+	walk(err, func(e *appError) bool {
+		found = e.template == t
+		return !found
+	})
+	return found
+}
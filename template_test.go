@@ -0,0 +1,49 @@
+package errors_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+var errUserNotFound = errors.Define(errors.KindNotFound, "user not found")
+
+func TestTemplate(t *testing.T) {
+	var hooked bool
+	remove := errors.OnError(func(e errors.Error) {
+		if e.Op() == "app.Get" {
+			hooked = errors.IsTemplate(e, errUserNotFound)
+		}
+	})
+	defer remove()
+
+	err := errors.E("app.Handle", errUserNotFound.New("app.Get"))
+	if !errors.IsTemplate(err, errUserNotFound) {
+		t.Error("IsTemplate() = false, want true")
+	}
+	if !hooked {
+		t.Error("hooks observed the error before its template was set")
+	}
+	if got := errors.Kind(err); got != errors.KindNotFound {
+		t.Errorf("Kind() = %d, want %d", got, errors.KindNotFound)
+	}
+	if got := errors.Msg(errUserNotFound.New("app.Get", "no such user")); got != "no such user" {
+		t.Errorf("Msg() = %q, want the overridden message", got)
+	}
+	if errors.IsTemplate(errors.E("app.Get", errors.KindNotFound, "user not found"), errUserNotFound) {
+		t.Error("IsTemplate() = true for an error not constructed from the template")
+	}
+}
+
+func TestTemplateStack(t *testing.T) {
+	pcs := errors.StackPCs(errUserNotFound.New("app.Get"))
+	if len(pcs) == 0 {
+		t.Fatal("no program counters captured")
+	}
+	fr, _ := runtime.CallersFrames(pcs).Next()
+	if !strings.HasSuffix(fr.Function, ".TestTemplateStack") {
+		t.Errorf("first frame = %s, want the caller of Template.New", fr.Function)
+	}
+}
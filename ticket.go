@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// ticketAlphabet is Crockford's base32 alphabet, which omits
+// I, L, O and U so that codes are easy to read out and type.
+const ticketAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// TicketCode returns a short code like "E-7F3K2" for users to report
+// to support. It is derived from the first 25 bits of Fingerprint,
+// so it identifies the failing code path without exposing internals
+// and is stable across releases as long as the fingerprint is.
+// It returns "" for nil.
+func TicketCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	return ticketCode(Fingerprint(err))
+}
+
+func ticketCode(fingerprint string) string {
+	b, err := hex.DecodeString(fingerprint[:8])
+	if err != nil {
+		return ""
+	}
+	n := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	var code [7]byte
+	code[0], code[1] = 'E', '-'
+	for i := 0; i < 5; i++ {
+		code[2+i] = ticketAlphabet[n>>(27-5*i)&0x1f]
+	}
+	return string(code[:])
+}
+
+// ticketNormalizer maps the letters mistaken for digits
+// to the digits as Crockford's base32 decoding does.
+var ticketNormalizer = strings.NewReplacer("O", "0", "I", "1", "L", "1")
+
+// ExplainTicketCode returns the first of candidates whose TicketCode is
+// code, or nil if none is. Codes are matched case-insensitively
+// and with or without the "E-" prefix. O is read as 0 and I and L
+// as 1 since users may type them for codes read from screens.
+func ExplainTicketCode(code string, candidates []error) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	code = ticketNormalizer.Replace(strings.TrimPrefix(code, "E-"))
+	code = "E-" + code
+	for _, err := range candidates {
+		if err != nil && TicketCode(err) == code {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package errors_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestExplainTicketCode(t *testing.T) {
+	// Pick errors whose codes have the digits users may type as letters.
+	var candidates []error
+	for i := 0; len(candidates) < 3 && i < 1000; i++ {
+		err := errors.E(errors.Op("app.Get"+strconv.Itoa(i)), errors.KindServiceUnavailable)
+		if strings.ContainsAny(strings.TrimPrefix(errors.TicketCode(err), "E-"), "01") {
+			candidates = append(candidates, err)
+		}
+	}
+	if len(candidates) < 3 {
+		t.Fatalf("found %d codes with 0 or 1, want 3", len(candidates))
+	}
+	typos := strings.NewReplacer("0", "O", "1", "I")
+
+	for _, err := range candidates {
+		code := errors.TicketCode(err)
+		tests := map[string]string{
+			"exact":     code,
+			"lower":     strings.ToLower(code),
+			"no prefix": strings.TrimPrefix(code, "E-"),
+			"O and I":   typos.Replace(code),
+			"lower L":   strings.ToLower(strings.ReplaceAll(code, "1", "L")),
+		}
+		for name, typed := range tests {
+			if got := errors.ExplainTicketCode(typed, candidates); got != err {
+				t.Errorf("%s: ExplainTicketCode(%q) = %v, want %v", name, typed, got, err)
+			}
+		}
+	}
+	if got := errors.ExplainTicketCode("E-UUUUU", candidates); got != nil {
+		t.Errorf("ExplainTicketCode(E-UUUUU) = %v, want nil", got)
+	}
+}
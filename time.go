@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var nowFunc atomic.Value
+
+func init() {
+	nowFunc.Store(time.Now)
+}
+
+// SetNow sets the clock used to timestamp errors.
+// It is intended for deterministic output in tests.
+// A nil clock restores time.Now.
+func SetNow(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	nowFunc.Store(now)
+}
+
+func now() time.Time {
+	return nowFunc.Load().(func() time.Time)()
+}
+
+// CreatedAt returns the time when the outermost layer constructed by E
+// was created, or the zero time if there is no such layer.
+func CreatedAt(err error) time.Time {
+	e, ok := outermost(err)
+	if !ok {
+		return time.Time{}
+	}
+	return e.created
+}
+
+func (err *appError) createdAt() *time.Time {
+	if err.created.IsZero() {
+		return nil
+	}
+	return &err.created
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"net/http"
+	"time"
+)
+
+// Transience marks whether an error is worth retrying.
+type Transience int
+
+const (
+	// Transient marks an error as transient.
+	Transient Transience = iota + 1
+	// Permanent marks an error as not transient.
+	Permanent
+)
+
+// IsTransient reports whether err is worth retrying.
+// An explicit Transient or Permanent mark in the chain wins, then
+// errors implementing Temporary() or Timeout() are transient, and
+// otherwise kinds 429, 502, 503 and 504 are transient.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	decided, transient := false, false
+	visit(err, func(c error) bool {
+		if e, ok := c.(*appError); ok {
+			if e.transience != 0 {
+				decided, transient = true, e.transience == Transient
+			}
+			return !decided
+		}
+		if t, ok := c.(interface{ Temporary() bool }); ok && t.Temporary() {
+			decided, transient = true, true
+		}
+		if t, ok := c.(interface{ Timeout() bool }); ok && t.Timeout() {
+			decided, transient = true, true
+		}
+		return !decided
+	})
+	if decided {
+		return transient
+	}
+	switch Kind(err) {
+	case KindTooManyRequests, http.StatusBadGateway, KindServiceUnavailable, KindGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// RetryAfter returns the duration passed to E directly or
+// with RetryAfterHeader as a hint of when to retry.
+func RetryAfter(err error) (time.Duration, bool) {
+	var d time.Duration
+	walk(err, func(e *appError) bool {
+		d = e.retryAfter
+		return d <= 0
+	})
+	return d, d > 0
+}
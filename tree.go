@@ -0,0 +1,114 @@
+package errors
+
+import (
+	stderrors "errors"
+	"strconv"
+	"strings"
+
+	"go.nownabe.dev/log"
+)
+
+// Node is a node of the tree of an error's chain.
+// Errors not constructed by E have only Msg with their texts.
+type Node struct {
+	Op       Op        `json:"op,omitempty"`
+	Kind     KindCode  `json:"kind,omitempty"`
+	Msg      string    `json:"msg,omitempty"`
+	Level    log.Level `json:"level,omitempty"`
+	Location *Frame    `json:"location,omitempty"`
+	Children []*Node   `json:"children,omitempty"`
+
+	// err is the error of this node, or nil for the marker
+	// of the layers beyond the maximum depth.
+	err error
+}
+
+// Tree returns the tree of err's chain. Kinds and levels are
+// those set on each layer. Multiple errors become children
+// of the layer wrapping them. Beyond the maximum depth set by
+// SetMaxDepth, the tree ends with a node of the marker.
+func Tree(err error) *Node {
+	if isNil(err) {
+		return nil
+	}
+	b := treeBuilder{n: 1, limit: depthLimit()}
+	return b.node(err)
+}
+
+// treeBuilder builds a tree counting the errors in n
+// as visitN does so that self-referential chains terminate.
+type treeBuilder struct {
+	n, limit  int
+	truncated bool
+}
+
+func (b *treeBuilder) node(err error) *Node {
+	e, ok := err.(*appError)
+	if !ok {
+		n := &Node{Msg: err.Error(), err: err}
+		if m, ok := err.(interface{ Unwrap() []error }); ok {
+			n.Children = b.join(m.Unwrap())
+		} else {
+			n.Children = b.children(stderrors.Unwrap(err))
+		}
+		return n
+	}
+	n := &Node{Op: e.op, Kind: e.kind, Msg: e.msg, Level: e.level, err: e}
+	if fr, ok := e.location(); ok {
+		n.Location = &fr
+	}
+	n.Children = b.children(e.err)
+	return n
+}
+
+func (b *treeBuilder) children(err error) []*Node {
+	if isNil(err) || b.truncated {
+		return nil
+	}
+	if b.n >= b.limit {
+		b.truncated = true
+		return []*Node{{Msg: truncation()}}
+	}
+	b.n++
+	if m, ok := err.(interface{ Unwrap() []error }); ok {
+		return b.join(m.Unwrap())
+	}
+	return []*Node{b.node(err)}
+}
+
+func (b *treeBuilder) join(errs []error) []*Node {
+	var nodes []*Node
+	for _, err := range errs {
+		nodes = append(nodes, b.children(err)...)
+	}
+	return nodes
+}
+
+// String renders the tree indented by depth, one node per line.
+func (n *Node) String() string {
+	var b strings.Builder
+	n.write(&b, 0)
+	return b.String()
+}
+
+func (n *Node) write(b *strings.Builder, depth int) {
+	var parts []string
+	if n.Op != "" {
+		parts = append(parts, string(n.Op)+":")
+	}
+	if n.Kind != 0 {
+		parts = append(parts, "["+strconv.Itoa(int(n.Kind))+" "+n.Kind.String()+"]")
+	}
+	if n.Msg != "" {
+		parts = append(parts, n.Msg)
+	}
+	if n.Location != nil {
+		parts = append(parts, "("+n.Location.File+":"+strconv.Itoa(n.Location.Line)+")")
+	}
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(strings.Join(parts, " "))
+	b.WriteString("\n")
+	for _, c := range n.Children {
+		c.write(b, depth+1)
+	}
+}
@@ -0,0 +1,94 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"go.nownabe.dev/errors"
+)
+
+func TestTree(t *testing.T) {
+	base := stderrors.New("connection refused")
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"nil": {
+			err:  nil,
+			want: "",
+		},
+		"plain": {
+			err:  fmt.Errorf("dial: %w", base),
+			want: "dial: connection refused\n  connection refused\n",
+		},
+		"chain": {
+			err: errors.E("app.Get", errors.KindNotFound, "no user", errors.NoStack,
+				errors.E("db.Query", fmt.Errorf("dial: %w", base), errors.NoStack)),
+			want: "app.Get: [404 Not Found] no user\n" +
+				"  db.Query:\n" +
+				"    dial: connection refused\n" +
+				"      connection refused\n",
+		},
+		"multiple": {
+			err: errors.E("app.Sync", errors.NoStack,
+				errors.E("app.Push", errors.KindServiceUnavailable, errors.NoStack), base),
+			want: "app.Sync:\n" +
+				"  app.Push: [503 Service Unavailable]\n" +
+				"  connection refused\n",
+		},
+		"join": {
+			err: stderrors.Join(errors.E("app.Push", errors.NoStack), base),
+			want: "app.Push\nconnection refused\n" +
+				"  app.Push:\n" +
+				"  connection refused\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tree := errors.Tree(tt.err)
+			if tt.err == nil {
+				if tree != nil {
+					t.Errorf("Tree() = %v, want nil", tree)
+				}
+				return
+			}
+			if got := tree.String(); got != tt.want {
+				t.Errorf("Tree() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTreeMaxDepth(t *testing.T) {
+	const marker = "... more than 128 layers"
+
+	depth := func(n *errors.Node) (int, *errors.Node) {
+		d := 1
+		for len(n.Children) > 0 {
+			n = n.Children[0]
+			d++
+		}
+		return d, n
+	}
+
+	tests := map[string]error{
+		"deep":   chain(40001),
+		"cyclic": errors.E("app.Get", errors.KindNotFound, &cyclicError{}),
+	}
+	for name, err := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, last := depth(errors.Tree(err))
+			if d != 129 || last.Msg != marker {
+				t.Errorf("Tree() has %d nodes ending with %q, want 128 nodes and the marker", d, last.Msg)
+			}
+		})
+	}
+
+	errors.SetMaxDepth(256)
+	defer errors.SetMaxDepth(128)
+	d, last := depth(errors.Tree(chain(200)))
+	if d != 200 || last.Msg != "no user" {
+		t.Errorf("Tree() has %d nodes ending with %q, want the whole chain", d, last.Msg)
+	}
+}
@@ -0,0 +1,94 @@
+// Package twirperrors converts errors of go.nownabe.dev/errors
+// from and into Twirp errors.
+package twirperrors // import "go.nownabe.dev/errors/twirperrors"
+
+import (
+	stderrors "errors"
+	"strconv"
+	"strings"
+
+	"github.com/twitchtv/twirp"
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+const (
+	// OpsKey is the metadata key of ops joined by ",".
+	OpsKey = "ops"
+	// LevelKey is the metadata key of the numeric level.
+	LevelKey = "level"
+)
+
+var codesByKind = map[errors.KindCode]twirp.ErrorCode{
+	errors.KindBadRequest:      twirp.InvalidArgument,
+	errors.KindUnauthorized:    twirp.Unauthenticated,
+	errors.KindForbidden:       twirp.PermissionDenied,
+	errors.KindNotFound:        twirp.NotFound,
+	errors.KindTooManyRequests: twirp.ResourceExhausted,
+	errors.KindUnexpected:      twirp.Internal,
+}
+
+var kindsByCode = func() map[twirp.ErrorCode]errors.KindCode {
+	m := make(map[twirp.ErrorCode]errors.KindCode, len(codesByKind))
+	for k, c := range codesByKind {
+		m[c] = k
+	}
+	return m
+}()
+
+// TwirpCode returns the Twirp code corresponding to the error's kind.
+// Plain errors are mapped to twirp.Internal and unknown kinds
+// to twirp.Unknown.
+func TwirpCode(err error) twirp.ErrorCode {
+	var e errors.Error
+	if !stderrors.As(err, &e) {
+		return twirp.Internal
+	}
+	if c, ok := codesByKind[errors.Kind(err)]; ok {
+		return c
+	}
+	return twirp.Unknown
+}
+
+// ToTwirp converts the error into a Twirp error
+// which carries errors.Msg as its message and the ops and
+// the level as metadata.
+func ToTwirp(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+	terr := twirp.NewError(TwirpCode(err), errors.Msg(err))
+	if ops := errors.Ops(err); len(ops) > 0 {
+		terr = terr.WithMeta(OpsKey, strings.Join(ops, ","))
+	}
+	return terr.WithMeta(LevelKey, strconv.Itoa(int(errors.Level(err))))
+}
+
+// FromTwirp constructs an error from a Twirp error received by a client.
+// The kind is taken from the code and the level from the metadata.
+// Codes without corresponding kinds are KindUnexpected.
+// Metadata are attached as fields.
+func FromTwirp(op errors.Op, terr twirp.Error) error {
+	if terr == nil {
+		return nil
+	}
+	kind, ok := kindsByCode[terr.Code()]
+	if !ok {
+		kind = errors.KindUnexpected
+	}
+	args := []interface{}{kind}
+	if msg := terr.Msg(); msg != "" {
+		args = append(args, msg)
+	}
+	if l, err := strconv.Atoi(terr.Meta(LevelKey)); err == nil && l > 0 {
+		args = append(args, log.Level(l))
+	}
+	if meta := terr.MetaMap(); len(meta) > 0 {
+		fields := errors.Fields{}
+		for k, v := range meta {
+			fields[k] = v
+		}
+		args = append(args, fields)
+	}
+	return errors.ECaller(1, op, args...)
+}
@@ -0,0 +1,85 @@
+package twirperrors_test
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/twitchtv/twirp"
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/errors/twirperrors"
+	"go.nownabe.dev/log"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		kind errors.KindCode
+		code twirp.ErrorCode
+	}{
+		{errors.KindBadRequest, twirp.InvalidArgument},
+		{errors.KindUnauthorized, twirp.Unauthenticated},
+		{errors.KindForbidden, twirp.PermissionDenied},
+		{errors.KindNotFound, twirp.NotFound},
+		{errors.KindTooManyRequests, twirp.ResourceExhausted},
+		{errors.KindUnexpected, twirp.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			err := errors.E("app.Handle", errors.E("app.Get", tt.kind, log.LevelWarn, "failed"))
+
+			terr := twirperrors.ToTwirp(err)
+			if terr.Code() != tt.code {
+				t.Errorf("Code() = %s, want %s", terr.Code(), tt.code)
+			}
+			if got, want := terr.Meta(twirperrors.OpsKey), "app.Handle,app.Get"; got != want {
+				t.Errorf("Meta(OpsKey) = %q, want %q", got, want)
+			}
+
+			got := twirperrors.FromTwirp("client.Get", terr)
+			if k := errors.Kind(got); k != tt.kind {
+				t.Errorf("Kind() = %d, want %d", k, tt.kind)
+			}
+			if l := errors.Level(got); l != log.LevelWarn {
+				t.Errorf("Level() = %v, want %v", l, log.LevelWarn)
+			}
+			if msg := errors.Msg(got); msg != "failed" {
+				t.Errorf("Msg() = %q, want failed", msg)
+			}
+			if ops := errors.FieldsOf(got)[twirperrors.OpsKey]; ops != "app.Handle,app.Get" {
+				t.Errorf("FieldsOf()[OpsKey] = %v, want the remote ops", ops)
+			}
+		})
+	}
+}
+
+func TestTwirpCode(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want twirp.ErrorCode
+	}{
+		"plain":   {stderrors.New("failed"), twirp.Internal},
+		"unknown": {errors.E("app.Get", errors.KindGone), twirp.Unknown},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := twirperrors.TwirpCode(tt.err); got != tt.want {
+				t.Errorf("TwirpCode() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromTwirpUnmapped(t *testing.T) {
+	err := twirperrors.FromTwirp("client.Get", twirp.NewError(twirp.DataLoss, "lost"))
+	if got := errors.Kind(err); got != errors.KindUnexpected {
+		t.Errorf("Kind() = %d, want %d", got, errors.KindUnexpected)
+	}
+}
+
+func TestNil(t *testing.T) {
+	if terr := twirperrors.ToTwirp(nil); terr != nil {
+		t.Errorf("ToTwirp(nil) = %v, want nil", terr)
+	}
+	if err := twirperrors.FromTwirp("client.Get", nil); err != nil {
+		t.Errorf("FromTwirp(nil) = %v, want nil", err)
+	}
+}
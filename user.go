@@ -0,0 +1,28 @@
+package errors
+
+// User can be passed to E to attach the identity of the authenticated
+// user. It is included in JSON marshaling, slog values and %+v
+// but never in client messages.
+type User string
+
+// UserOf returns the innermost user identity set in err's chain.
+// ok is false if no layer sets a user.
+func UserOf(err error) (string, bool) {
+	var user User
+	walk(err, func(e *appError) bool {
+		if e.user != "" {
+			user = e.user
+		}
+		return true
+	})
+	return string(user), user != ""
+}
+
+// userOf returns the redacted user identity for renderers.
+func userOf(err error) string {
+	user, ok := UserOf(err)
+	if !ok {
+		return ""
+	}
+	return redact(user)
+}
@@ -0,0 +1,29 @@
+package errors
+
+// With annotates err with args without adding a layer.
+// If err was constructed by E, it returns a copy of err with
+// the message, kind, level and fields of args merged in,
+// and errors in args are wrapped alongside the original ones.
+// The original error is never mutated.
+// Otherwise, it wraps err with E and an empty op.
+func With(err error, args ...interface{}) error {
+	e, ok := err.(*appError)
+	if !ok {
+		return E("", append([]interface{}{err}, args...)...)
+	}
+
+	c := *e
+	c.fields = Fields(nil).merge(e.fields)
+	c.related = append([]error(nil), e.related...)
+	c.headers = append([]HTTPHeader(nil), e.headers...)
+	c.details = append([]Detail(nil), e.details...)
+	c.args = append(ArgValues(nil), e.args...)
+	errs, wrapped := c.set(args)
+	if len(errs) > 0 && c.err != nil {
+		errs = append([]error{c.err}, errs...)
+	}
+	if len(errs) > 0 || wrapped != nil {
+		c.wrap(errs, wrapped)
+	}
+	return &c
+}
@@ -0,0 +1,64 @@
+package errors_test
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/log"
+)
+
+func TestWith(t *testing.T) {
+	orig := errors.E("app.Get", errors.KindNotFound, "no user", errors.Fields{"id": 1})
+	got := errors.With(orig, errors.KindBadRequest, log.LevelWarn, "bad id", errors.Fields{"name": "x"})
+
+	if n := len(errors.Ops(got)); n != 1 {
+		t.Errorf("got %d layers, want 1", n)
+	}
+	if k := errors.Kind(got); k != errors.KindBadRequest {
+		t.Errorf("Kind() = %d, want %d", k, errors.KindBadRequest)
+	}
+	if l := errors.Level(got); l != log.LevelWarn {
+		t.Errorf("Level() = %v, want %v", l, log.LevelWarn)
+	}
+	if m := errors.Msg(got); m != "bad id" {
+		t.Errorf("Msg() = %q, want %q", m, "bad id")
+	}
+	fields := errors.FieldsOf(got)
+	if fields["id"] != 1 || fields["name"] != "x" {
+		t.Errorf("FieldsOf() = %v, want both fields", fields)
+	}
+
+	if k := errors.Kind(orig); k != errors.KindNotFound {
+		t.Errorf("original Kind() = %d, want %d", k, errors.KindNotFound)
+	}
+	if m := errors.Msg(orig); m != "no user" {
+		t.Errorf("original Msg() = %q, want %q", m, "no user")
+	}
+	if fields := errors.FieldsOf(orig); len(fields) != 1 {
+		t.Errorf("original FieldsOf() = %v, want only id", fields)
+	}
+}
+
+func TestWithWrapsErrors(t *testing.T) {
+	cause := stderrors.New("cause")
+	orig := errors.E("app.Get", "no user")
+	got := errors.With(orig, cause)
+	if !stderrors.Is(got, cause) {
+		t.Error("errors.Is(got, cause) = false, want true")
+	}
+	if stderrors.Unwrap(orig) != nil {
+		t.Error("the original error was mutated to wrap cause")
+	}
+}
+
+func TestWithPlain(t *testing.T) {
+	cause := stderrors.New("cause")
+	got := errors.With(cause, errors.KindConflict)
+	if !stderrors.Is(got, cause) {
+		t.Error("errors.Is(got, cause) = false, want true")
+	}
+	if k := errors.Kind(got); k != errors.KindConflict {
+		t.Errorf("Kind() = %d, want %d", k, errors.KindConflict)
+	}
+}
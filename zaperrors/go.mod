@@ -0,0 +1,17 @@
+module go.nownabe.dev/errors/zaperrors
+
+require (
+	go.nownabe.dev/errors v0.0.0
+	go.nownabe.dev/log v1.0.2
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+)
+
+replace go.nownabe.dev/errors => ../
+
+go 1.23
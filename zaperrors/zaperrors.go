@@ -0,0 +1,78 @@
+// Package zaperrors integrates go.nownabe.dev/errors with zap.
+package zaperrors // import "go.nownabe.dev/errors/zaperrors"
+
+import (
+	stderrors "errors"
+
+	"go.nownabe.dev/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Key is the field key used by Field.
+const Key = "error"
+
+// Field returns a zap field that encodes err as an object.
+// Errors not constructed by E are encoded with their messages only.
+func Field(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(Key, object{err})
+}
+
+type object struct {
+	err error
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (o object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var e errors.Error
+	if !stderrors.As(o.err, &e) {
+		enc.AddString("message", o.err.Error())
+		return nil
+	}
+	enc.AddString("message", errors.Msg(o.err))
+	enc.AddInt("kind", int(errors.Kind(o.err)))
+	enc.AddString("kind_text", errors.KindText(o.err))
+	enc.AddString("level", errors.Level(o.err).String())
+	if err := enc.AddArray("ops", ops(errors.Ops(o.err))); err != nil {
+		return err
+	}
+	if root := errors.Root(o.err); root != nil && root != o.err {
+		enc.AddString("root", errors.Msg(root))
+	}
+	return enc.AddArray("stacktrace", frames(errors.Frames(o.err)))
+}
+
+type ops []string
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (ops ops) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, op := range ops {
+		enc.AppendString(op)
+	}
+	return nil
+}
+
+type frames []errors.Frame
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (frs frames) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, fr := range frs {
+		if err := enc.AppendObject(frame(fr)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type frame errors.Frame
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (fr frame) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("function", fr.Function)
+	enc.AddString("file", fr.File)
+	enc.AddInt("line", fr.Line)
+	return nil
+}
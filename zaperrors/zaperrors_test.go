@@ -0,0 +1,93 @@
+package zaperrors_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/errors"
+	"go.nownabe.dev/errors/zaperrors"
+	"go.nownabe.dev/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func logged(t *testing.T, err error) map[string]interface{} {
+	t.Helper()
+	core, logs := observer.New(zapcore.DebugLevel)
+	zap.New(core).Error("failed", zaperrors.Field(err))
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	obj, ok := entries[0].ContextMap()[zaperrors.Key].(map[string]interface{})
+	if !ok {
+		t.Fatalf("field %q = %v, want an object", zaperrors.Key, entries[0].ContextMap())
+	}
+	return obj
+}
+
+func TestField(t *testing.T) {
+	root := stderrors.New("connection refused")
+	err := errors.E("app.Handle", "handle", fmt.Errorf("query: %w",
+		errors.E("app.Get", errors.KindNotFound, log.LevelWarn, "no user", root)))
+
+	obj := logged(t, err)
+	want := map[string]interface{}{
+		"message":   "handle: no user",
+		"kind":      404,
+		"kind_text": "Not Found",
+		"level":     log.LevelWarn.String(),
+		"ops":       []interface{}{"app.Handle", "app.Get"},
+		"root":      "connection refused",
+	}
+	for k, w := range want {
+		if g := obj[k]; !reflect.DeepEqual(g, w) {
+			t.Errorf("%s = %#v, want %#v", k, g, w)
+		}
+	}
+
+	frames, _ := obj["stacktrace"].([]interface{})
+	if len(frames) == 0 {
+		t.Fatal("stacktrace is empty")
+	}
+	fr, _ := frames[0].(map[string]interface{})
+	if fn, _ := fr["function"].(string); !strings.HasSuffix(fn, ".TestField") {
+		t.Errorf("function = %q, want TestField", fn)
+	}
+	if file, _ := fr["file"].(string); !strings.HasSuffix(file, "zaperrors_test.go") {
+		t.Errorf("file = %q, want zaperrors_test.go", file)
+	}
+	if line, _ := fr["line"].(int); line == 0 {
+		t.Error("line = 0")
+	}
+}
+
+func TestFieldLeaf(t *testing.T) {
+	obj := logged(t, errors.E("app.Get", errors.KindNotFound, errors.NoStack))
+	if _, ok := obj["root"]; ok {
+		t.Errorf("root = %v, want none for a leaf", obj["root"])
+	}
+	if frames, _ := obj["stacktrace"].([]interface{}); len(frames) != 0 {
+		t.Errorf("stacktrace = %v, want empty", frames)
+	}
+}
+
+func TestFieldPlain(t *testing.T) {
+	obj := logged(t, stderrors.New("connection refused"))
+	want := map[string]interface{}{"message": "connection refused"}
+	if !reflect.DeepEqual(obj, want) {
+		t.Errorf("field = %v, want %v", obj, want)
+	}
+}
+
+func TestFieldNil(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	zap.New(core).Error("failed", zaperrors.Field(nil))
+	if m := logs.All()[0].ContextMap(); len(m) != 0 {
+		t.Errorf("fields = %v, want none", m)
+	}
+}